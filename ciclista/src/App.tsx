@@ -7,6 +7,7 @@ interface FormData {
   apellido_materno: string;
   email: string;
   sexo: 'M' | 'F' | '';
+  fecha_nacimiento: string;
   categoria: string;
   pago_realizado: boolean;
   ine_path: string;
@@ -20,8 +21,9 @@ function App() {
     apellido_materno: '',
     email: '',
     sexo: '',
-    categoria: 'Aficionado',
-    pago_realizado: true,
+    fecha_nacimiento: '',
+    categoria: '',
+    pago_realizado: false,
     ine_path: '',
     comprobante_pago_path: '',
   });
@@ -48,7 +50,9 @@ function App() {
     if (data.apellido_materno) dslString += `apellido_materno: "${data.apellido_materno}";\n`;
     dslString += `email: "${data.email}";\n`;
     dslString += `sexo: "${data.sexo}";\n`;
-    dslString += `categoria: "${data.categoria}";\n`;
+    dslString += `fecha_nacimiento: ${data.fecha_nacimiento};\n`;
+    // Sin categoría el servidor la asigna según la edad
+    if (data.categoria) dslString += `categoria: "${data.categoria}";\n`;
     dslString += `pago_realizado: ${data.pago_realizado};\n`;
     if (data.ine_path) dslString += `ine_path: "${data.ine_path}";\n`;
     if (data.comprobante_pago_path) dslString += `comprobante_pago_path: "${data.comprobante_pago_path}";\n`;
@@ -75,6 +79,9 @@ function App() {
       console.log("Respuesta del servidor:", result);
 
       if (!response.ok) {
+        if (Array.isArray(result.diagnostics)) {
+          throw new Error(result.diagnostics.map((d: { message: string }) => d.message).join('\n'));
+        }
         throw new Error(JSON.stringify(result.error) || 'Ocurrió un error en el servidor');
       }
       
@@ -120,16 +127,25 @@ function App() {
           <input name="nombre" value={formData.nombre} onChange={handleInputChange} placeholder="Nombre(s)" required />
           <input name="apellido_paterno" value={formData.apellido_paterno} onChange={handleInputChange} placeholder="Apellido Paterno" required />
           <input name="apellido_materno" value={formData.apellido_materno} onChange={handleInputChange} placeholder="Apellido Materno" />
-          <input name="email" type="email" value={formData.email} onChange={handleInputChange} placeholder="Correo Electrónico (Gmail)" required />
+          <input name="email" type="email" value={formData.email} onChange={handleInputChange} placeholder="Correo Electrónico" required />
           <select name="sexo" value={formData.sexo} onChange={handleInputChange} required>
             <option value="" disabled>Selecciona tu sexo...</option>
             <option value="M">Masculino</option>
             <option value="F">Femenino</option>
           </select>
+          <input name="fecha_nacimiento" type="date" value={formData.fecha_nacimiento} onChange={handleInputChange} required />
           <select name="categoria" value={formData.categoria} onChange={handleInputChange}>
+            <option value="">Categoría automática (por edad)</option>
+            <option value="Infantil">Infantil</option>
+            <option value="Cadete">Cadete</option>
+            <option value="Juvenil">Juvenil</option>
+            <option value="Sub-23">Sub-23</option>
             <option value="Elite">Elite</option>
+            <option value="Master A">Master A</option>
+            <option value="Master B">Master B</option>
+            <option value="Master C">Master C</option>
+            <option value="Master D">Master D</option>
             <option value="Aficionado">Aficionado</option>
-            <option value="Juvenil">Juvenil</option>
           </select>
           <input name="ine_path" value={formData.ine_path} onChange={handleInputChange} placeholder="Ruta simulada INE (ej: /docs/ine.pdf)" />
           <input name="comprobante_pago_path" value={formData.comprobante_pago_path} onChange={handleInputChange} placeholder="Ruta simulada Pago (ej: /docs/pago.pdf)" />
@@ -0,0 +1,119 @@
+// ciclistafmt reescribe documentos del DSL de registro en forma canónica.
+//
+// Uso:
+//
+//	ciclistafmt [-w] [-l] [-lang es|en] [archivo ...]
+//
+// Sin archivos lee de la entrada estándar y escribe en la salida estándar.
+package main
+
+import (
+	"bytes"
+	"compilerciclista/src/diagnostic"
+	"compilerciclista/src/format"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+var (
+	write    = flag.Bool("w", false, "sobrescribir cada archivo con su versión formateada")
+	list     = flag.Bool("l", false, "solo listar los archivos cuyo formato difiere del canónico")
+	langFlag = flag.String("lang", string(diagnostic.DefaultLang), "idioma de los mensajes de error (es o en)")
+)
+
+// lang es el idioma elegido con -lang.
+var lang diagnostic.Lang
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "uso: ciclistafmt [-w] [-l] [-lang es|en] [archivo ...]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := setLang(*langFlag); err != nil {
+		fmt.Fprintf(os.Stderr, "ciclistafmt: %v\n", err)
+		os.Exit(2)
+	}
+
+	if flag.NArg() == 0 {
+		if *write {
+			fmt.Fprintln(os.Stderr, "ciclistafmt: -w requiere al menos un archivo")
+			os.Exit(2)
+		}
+		input, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ciclistafmt: %v\n", err)
+			os.Exit(1)
+		}
+		if !processFile("<stdin>", input) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	ok := true
+	for _, path := range flag.Args() {
+		input, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ciclistafmt: %v\n", err)
+			ok = false
+			continue
+		}
+		if !processFile(path, input) {
+			ok = false
+		}
+	}
+	if !ok {
+		os.Exit(1)
+	}
+}
+
+// setLang fija el idioma de los mensajes a partir del valor de -lang, que
+// admite variantes regionales como "en-US".
+func setLang(value string) error {
+	parsed, supported := diagnostic.ParseLang(value)
+	if !supported {
+		return fmt.Errorf("idioma no soportado %q", value)
+	}
+	lang = parsed
+	return nil
+}
+
+// processFile formatea un documento y lo escribe según las banderas.
+// Devuelve false si el documento tenía errores o no se pudo escribir.
+func processFile(path string, input []byte) bool {
+	formatted, errors := format.Source(string(input))
+	if len(errors) > 0 {
+		printDiagnostics(os.Stderr, path, errors)
+		return false
+	}
+
+	changed := !bytes.Equal(input, []byte(formatted))
+	switch {
+	case *list:
+		if changed {
+			fmt.Println(path)
+		}
+	case *write:
+		if changed {
+			if err := os.WriteFile(path, []byte(formatted), 0644); err != nil {
+				fmt.Fprintf(os.Stderr, "ciclistafmt: %v\n", err)
+				return false
+			}
+		}
+	default:
+		fmt.Print(formatted)
+	}
+	return true
+}
+
+// printDiagnostics escribe los errores en el idioma de -lang, uno por línea
+// con la posición en la que empiezan.
+func printDiagnostics(w io.Writer, path string, diagnostics []diagnostic.Diagnostic) {
+	for _, d := range diagnostic.LocalizeAll(diagnostics, lang) {
+		fmt.Fprintf(w, "%s:%d:%d: %s\n", path, d.Span.Start.Line, d.Span.Start.Column, d.Message)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"compilerciclista/src/diagnostic"
+	"compilerciclista/src/format"
+	"flag"
+	"testing"
+)
+
+func TestLangFlag(t *testing.T) {
+	t.Cleanup(func() { lang = diagnostic.DefaultLang })
+	_, errors := format.Source(`nombre "Ana";`)
+	if len(errors) == 0 {
+		t.Fatal("el documento de prueba no tiene errores")
+	}
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"es", "doc.ciclista:1:8: Error de sintaxis: se esperaba el token :, se obtuvo STRING\n"},
+		{"en", "doc.ciclista:1:8: Syntax error: expected token :, got STRING\n"},
+		{"en-US", "doc.ciclista:1:8: Syntax error: expected token :, got STRING\n"},
+		{"ES_mx", "doc.ciclista:1:8: Error de sintaxis: se esperaba el token :, se obtuvo STRING\n"},
+	}
+	for _, tt := range tests {
+		if err := flag.Set("lang", tt.value); err != nil {
+			t.Fatal(err)
+		}
+		if err := setLang(*langFlag); err != nil {
+			t.Errorf("-lang %s: %v", tt.value, err)
+			continue
+		}
+		var out bytes.Buffer
+		printDiagnostics(&out, "doc.ciclista", errors)
+		if out.String() != tt.want {
+			t.Errorf("-lang %s escribió %q, se esperaba %q", tt.value, out.String(), tt.want)
+		}
+	}
+
+	lang = diagnostic.English
+	if err := setLang("fr"); err == nil {
+		t.Error("-lang fr fue aceptado")
+	}
+	if lang != diagnostic.English {
+		t.Errorf("un idioma no soportado cambió el idioma a %q", lang)
+	}
+}
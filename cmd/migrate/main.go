@@ -0,0 +1,108 @@
+// migrate aplica, revierte o muestra las migraciones del esquema de la base
+// de datos configurada en .env (DB_DRIVER y los datos de conexión).
+//
+// Uso:
+//
+//	migrate up              aplica todas las migraciones pendientes
+//	migrate down [n]        revierte las últimas n migraciones (1 por defecto)
+//	migrate status          muestra qué migraciones están aplicadas
+//	migrate baseline <v>    marca como aplicadas las migraciones hasta v sin
+//	                        ejecutarlas (bases de datos creadas con el script)
+package main
+
+import (
+	"compilerciclista/src/database"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "uso: migrate up | down [n] | status | baseline <versión>")
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	godotenv.Load()
+	driver, dsn := database.ConfigFromEnv()
+	db, err := database.Open(driver, dsn)
+	if err != nil {
+		fail(fmt.Errorf("no se pudo conectar a la base de datos: %w", err))
+	}
+	defer db.Close()
+
+	switch flag.Arg(0) {
+	case "up":
+		applied, err := database.MigrateUp(db, driver)
+		for _, m := range applied {
+			fmt.Printf("aplicada   %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			fail(err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("El esquema ya está al día.")
+		}
+	case "down":
+		reverted, err := database.MigrateDown(db, driver, argNumber(1, 1))
+		for _, m := range reverted {
+			fmt.Printf("revertida  %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			fail(err)
+		}
+	case "status":
+		printStatus(db, driver)
+	case "baseline":
+		if flag.NArg() < 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		if err := database.MarkApplied(db, driver, argNumber(1, 0)); err != nil {
+			fail(err)
+		}
+		printStatus(db, driver)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func printStatus(db *sql.DB, driver string) {
+	statuses, err := database.MigrationStatuses(db, driver)
+	if err != nil {
+		fail(err)
+	}
+	for _, s := range statuses {
+		state := "pendiente"
+		if s.AppliedAt != nil {
+			state = "aplicada el " + s.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%04d_%-28s %s\n", s.Version, s.Name, state)
+	}
+}
+
+// argNumber lee el argumento i como número positivo, o devuelve def si no está.
+func argNumber(i, def int) int {
+	if flag.NArg() <= i {
+		return def
+	}
+	n, err := strconv.Atoi(flag.Arg(i))
+	if err != nil || n < 1 {
+		fail(fmt.Errorf("'%s' no es un número válido", flag.Arg(i)))
+	}
+	return n
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "migrate: %v\n", err)
+	os.Exit(1)
+}
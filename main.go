@@ -2,8 +2,11 @@ package main
 
 import (
 	"compilerciclista/src/database"
+	"compilerciclista/src/emailcheck"
 	"compilerciclista/src/handlers"
-	"fmt"
+	"compilerciclista/src/models"
+	"compilerciclista/src/schema"
+	"compilerciclista/src/semantic"
 	"log"
 	"net/http"
 	"os"
@@ -20,33 +23,91 @@ func main() {
 		log.Println("Advertencia: No se pudo encontrar el archivo .env, se usarán las variables de entorno del sistema.")
 	}
 
-	// 2. Construir la cadena de conexión (DSN) para MySQL
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true",
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_PORT"),
-		os.Getenv("DB_NAME"),
-	)
-
-	// 3. Inicializar la conexión a la base de datos
-	if err := database.InitDB(dsn); err != nil {
-		log.Fatalf("Error fatal: No se pudo conectar a la base de datos: %v", err)
+	// 2. Construir la cadena de conexión (DSN) según el motor: MySQL por
+	// defecto, o un archivo de SQLite para eventos pequeños sin servidor
+	driver, dsn := database.ConfigFromEnv()
+
+	// 3. Inicializar el almacenamiento de participantes y migrar su esquema.
+	// Con DB_DRIVER=memory no se necesita base de datos (los datos se pierden al cerrar).
+	var repo database.ParticipantRepository
+	var users database.UserRepository
+	if driver == "memory" {
+		memory := database.NewMemoryRepository()
+		repo, users = memory, memory
+		log.Println("Advertencia: Se usa almacenamiento en memoria; los registros no se guardarán.")
+	} else {
+		if err := database.InitDB(driver, dsn); err != nil {
+			log.Fatalf("Error fatal: No se pudo conectar a la base de datos: %v", err)
+		}
+		// Nos aseguramos de cerrar la conexión cuando la aplicación termine
+		defer database.DB.Close()
+		log.Printf("Conexión a la base de datos (%s) establecida exitosamente.", driver)
+		sqlRepo, err := database.NewRepository(driver, database.DB)
+		if err != nil {
+			log.Fatalf("Error fatal: %v", err)
+		}
+		repo, users = sqlRepo, sqlRepo
+	}
+
+	// 4. Cargar el esquema de validación del evento (si no se indica, se usa el incluido)
+	if schemaPath := os.Getenv("EVENT_SCHEMA_PATH"); schemaPath != "" {
+		eventSchema, err := schema.Load(schemaPath)
+		if err != nil {
+			log.Fatalf("Error fatal: No se pudo cargar el esquema del evento '%s': %v", schemaPath, err)
+		}
+		semantic.UseSchema(eventSchema)
+		log.Printf("Esquema del evento '%s' cargado desde %s", eventSchema.Event, schemaPath)
+	}
+	// Sin DNS (por ejemplo, en desarrollo) se puede omitir la consulta de registros MX
+	if os.Getenv("EMAIL_SKIP_MX") == "true" {
+		emailcheck.LookupMX = emailcheck.AcceptAllMX
+		log.Println("Advertencia: La verificación MX de los correos está desactivada (EMAIL_SKIP_MX).")
 	}
-	// Nos aseguramos de cerrar la conexión cuando la aplicación termine
-	defer database.DB.Close()
-	log.Println("Conexión a la base de datos establecida exitosamente.")
+
+	participants := handlers.NewParticipantHandler(repo)
+	auth := handlers.NewAuthHandler(users, repo)
+
+	// La primera cuenta de administrador se crea desde el .env; las demás, con POST /users
+	if username := os.Getenv("ADMIN_USERNAME"); username != "" {
+		created, err := auth.Bootstrap(username, os.Getenv("ADMIN_PASSWORD"))
+		if err != nil {
+			log.Fatalf("Error fatal: No se pudo crear la cuenta de administrador '%s': %v", username, err)
+		}
+		if created {
+			log.Printf("Cuenta de administrador '%s' creada.", username)
+		}
+	}
+
+	// Quién puede usar cada ruta de administración, según el rol de su token
+	admin := handlers.RequireRole(models.RolAdmin)
+	staff := handlers.RequireRole(models.RolAdmin, models.RolOrganizador, models.RolMesa, models.RolJuez)
+	desk := handlers.RequireRole(models.DeskRoles...)
+	officials := handlers.RequireRole(models.RolAdmin, models.RolOrganizador, models.RolJuez)
 
 	mux := http.NewServeMux()
-	mux.HandleFunc("/register", handlers.RegisterParticipantHandler)
+	mux.HandleFunc("POST /login", auth.Login)
+	mux.HandleFunc("POST /users", admin(auth.CreateUser))
+	mux.HandleFunc("/register", participants.Register)
+	mux.HandleFunc("/register/batch", desk(participants.RegisterBatch))
+	mux.HandleFunc("GET /participants", staff(participants.List))
+	mux.HandleFunc("GET /participants/{code}", staff(participants.Get))
+	mux.HandleFunc("PATCH /participants/{code}", desk(participants.Update)) // correcciones y pagos
+	mux.HandleFunc("POST /participants/{code}/withdraw", officials(participants.Withdraw))
+	mux.HandleFunc("POST /participants/{code}/reinstate", officials(participants.Reinstate))
+	mux.HandleFunc("GET /participants/{code}/history", staff(participants.History))
+	// Autoservicio del participante, con el token que recibió al registrarse
+	mux.HandleFunc("GET /me", handlers.RequireParticipant(participants.Me))
+	mux.HandleFunc("POST /me/documents", handlers.RequireParticipant(participants.UploadMyDocuments))
+	mux.HandleFunc("/format", handlers.FormatHandler)
 
 	// 5. Configurar el middleware de CORS
 	c := cors.New(cors.Options{
 		AllowedOrigins: []string{"http://localhost:5173"}, // <-- La URL de tu frontend de Vite
-		AllowedMethods:   []string{"POST", "GET", "OPTIONS"},
-		AllowedHeaders:   []string{"Content-Type"},
+		AllowedMethods:   []string{"POST", "GET", "PATCH", "OPTIONS"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization", "X-Request-ID"},
+		ExposedHeaders:   []string{"X-Request-ID"},
 	})
-	handler := c.Handler(mux) // Envuelve tu mux con el manejador de CORS
+	handler := c.Handler(handlers.WithRequestID(mux)) // Envuelve tu mux con el manejador de CORS
 
 	// 6. Iniciar el servidor HTTP usando el manejador con CORS
 	port := ":8080"
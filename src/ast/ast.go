@@ -0,0 +1,207 @@
+package ast
+
+import (
+	"compilerciclista/src/token"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Node es cualquier elemento del árbol sintáctico de un documento DSL.
+type Node interface {
+	TokenLiteral() string
+	String() string
+	Span() token.Span
+}
+
+// Value es el lado derecho de una declaración: un literal o una lista.
+type Value interface {
+	Node
+	// GoValue devuelve el valor de Go equivalente: string, bool, int64,
+	// float64, time.Time o []interface{}.
+	GoValue() interface{}
+}
+
+// Document es la raíz del árbol. Un documento simple solo tiene Statements;
+// uno de bloques (un lote de participantes, un esquema) solo tiene Blocks.
+// Comments conserva los comentarios del texto original en orden, para
+// herramientas como el formateador.
+type Document struct {
+	Statements []*Statement
+	Blocks     []*Block
+	Comments   []token.Token
+	EOF        token.Token
+}
+
+func (d *Document) TokenLiteral() string { return "" }
+func (d *Document) Span() token.Span {
+	start := token.Position{Offset: 0, Line: 1, Column: 1}
+	return token.Span{Start: start, End: d.EOF.Span.End}
+}
+func (d *Document) String() string {
+	var out strings.Builder
+	for _, s := range d.Statements {
+		out.WriteString(s.String())
+		out.WriteString("\n")
+	}
+	for _, b := range d.Blocks {
+		out.WriteString(b.String())
+		out.WriteString("\n")
+	}
+	return out.String()
+}
+
+// HasBlocks indica si el documento está formado por bloques 'nombre { ... }'.
+func (d *Document) HasBlocks() bool {
+	return len(d.Blocks) > 0
+}
+
+// Block es un bloque con nombre, ej: participante { nombre: "Ana"; }
+type Block struct {
+	Token      token.Token // el identificador que da nombre al bloque
+	Statements []*Statement
+	RBrace     token.Token
+}
+
+func (b *Block) TokenLiteral() string { return b.Token.Literal }
+func (b *Block) Span() token.Span {
+	return token.Span{Start: b.Token.Span.Start, End: b.RBrace.Span.End}
+}
+func (b *Block) String() string {
+	var out strings.Builder
+	out.WriteString(b.Token.Literal + " {")
+	for _, s := range b.Statements {
+		out.WriteString(" " + s.String())
+	}
+	out.WriteString(" }")
+	return out.String()
+}
+
+// Statement es una declaración 'clave: valor;'.
+type Statement struct {
+	Key       *Key
+	Value     Value
+	Semicolon token.Token
+}
+
+func (s *Statement) TokenLiteral() string { return s.Key.TokenLiteral() }
+func (s *Statement) Span() token.Span {
+	return token.Span{Start: s.Key.Span().Start, End: s.Semicolon.Span.End}
+}
+func (s *Statement) String() string {
+	return s.Key.String() + ": " + s.Value.String() + ";"
+}
+
+// Key es el identificador a la izquierda de ':'.
+type Key struct {
+	Token token.Token
+	Name  string
+}
+
+func (k *Key) TokenLiteral() string { return k.Token.Literal }
+func (k *Key) Span() token.Span     { return k.Token.Span }
+func (k *Key) String() string       { return k.Name }
+
+// StringLiteral es una cadena. Value ya tiene las secuencias de escape
+// interpretadas; Raw conserva el texto original con sus comillas.
+type StringLiteral struct {
+	Token token.Token
+	Value string
+	Raw   string
+}
+
+func (s *StringLiteral) TokenLiteral() string { return s.Token.Literal }
+func (s *StringLiteral) Span() token.Span     { return s.Token.Span }
+func (s *StringLiteral) String() string       { return Quote(s.Value) }
+func (s *StringLiteral) GoValue() interface{} { return s.Value }
+
+type BooleanLiteral struct {
+	Token token.Token
+	Value bool
+}
+
+func (b *BooleanLiteral) TokenLiteral() string { return b.Token.Literal }
+func (b *BooleanLiteral) Span() token.Span     { return b.Token.Span }
+func (b *BooleanLiteral) String() string       { return strconv.FormatBool(b.Value) }
+func (b *BooleanLiteral) GoValue() interface{} { return b.Value }
+
+type IntegerLiteral struct {
+	Token token.Token
+	Value int64
+}
+
+func (i *IntegerLiteral) TokenLiteral() string { return i.Token.Literal }
+func (i *IntegerLiteral) Span() token.Span     { return i.Token.Span }
+func (i *IntegerLiteral) String() string       { return i.Token.Literal }
+func (i *IntegerLiteral) GoValue() interface{} { return i.Value }
+
+type FloatLiteral struct {
+	Token token.Token
+	Value float64
+}
+
+func (f *FloatLiteral) TokenLiteral() string { return f.Token.Literal }
+func (f *FloatLiteral) Span() token.Span     { return f.Token.Span }
+func (f *FloatLiteral) String() string       { return f.Token.Literal }
+func (f *FloatLiteral) GoValue() interface{} { return f.Value }
+
+// DateLiteral es una fecha AAAA-MM-DD ya validada contra el calendario.
+type DateLiteral struct {
+	Token token.Token
+	Value time.Time
+}
+
+func (d *DateLiteral) TokenLiteral() string { return d.Token.Literal }
+func (d *DateLiteral) Span() token.Span     { return d.Token.Span }
+func (d *DateLiteral) String() string       { return d.Token.Literal }
+func (d *DateLiteral) GoValue() interface{} { return d.Value }
+
+// ListLiteral es una lista entre corchetes, ej: ["555 123", "555 456"].
+type ListLiteral struct {
+	Token    token.Token // el '['
+	Elements []Value
+	RBracket token.Token
+}
+
+func (l *ListLiteral) TokenLiteral() string { return l.Token.Literal }
+func (l *ListLiteral) Span() token.Span {
+	return token.Span{Start: l.Token.Span.Start, End: l.RBracket.Span.End}
+}
+func (l *ListLiteral) String() string {
+	items := make([]string, len(l.Elements))
+	for i, e := range l.Elements {
+		items[i] = e.String()
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+func (l *ListLiteral) GoValue() interface{} {
+	values := make([]interface{}, len(l.Elements))
+	for i, e := range l.Elements {
+		values[i] = e.GoValue()
+	}
+	return values
+}
+
+// Quote escribe s como cadena del DSL, escapando solo lo que el lexer exige.
+func Quote(s string) string {
+	var out strings.Builder
+	out.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			out.WriteString(`\"`)
+		case '\\':
+			out.WriteString(`\\`)
+		case '\n':
+			out.WriteString(`\n`)
+		case '\t':
+			out.WriteString(`\t`)
+		case '\r':
+			out.WriteString(`\r`)
+		default:
+			out.WriteRune(r)
+		}
+	}
+	out.WriteByte('"')
+	return out.String()
+}
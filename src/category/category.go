@@ -0,0 +1,89 @@
+// Package category calcula la categoría de competencia de un participante a
+// partir de su edad el día de la carrera y su sexo, al estilo de la UCI.
+package category
+
+import "time"
+
+// Category es un rango de edades, opcionalmente limitado a un sexo. Una
+// categoría abierta (por ejemplo, de aficionados) no depende de la edad: la
+// puede elegir cualquiera, pero nunca se asigna automáticamente.
+type Category struct {
+	Name   string
+	MinAge int    // 0 si no hay límite inferior
+	MaxAge int    // 0 si no hay límite superior
+	Sexo   string // "" si aplica a todos
+	Open   bool
+}
+
+// Matches indica si la categoría corresponde a la edad y el sexo dados.
+func (c Category) Matches(age int, sexo string) bool {
+	if c.Sexo != "" && c.Sexo != sexo {
+		return false
+	}
+	if c.MinAge > 0 && age < c.MinAge {
+		return false
+	}
+	if c.MaxAge > 0 && age > c.MaxAge {
+		return false
+	}
+	return true
+}
+
+// Table es la lista de categorías de un evento, en orden de prioridad: se
+// asigna la primera que corresponda.
+type Table []Category
+
+// Assign devuelve la categoría por edad que corresponde a la edad y el sexo;
+// las categorías abiertas no se asignan.
+func (t Table) Assign(age int, sexo string) (Category, bool) {
+	for _, c := range t {
+		if !c.Open && c.Matches(age, sexo) {
+			return c, true
+		}
+	}
+	return Category{}, false
+}
+
+// IsOpen indica si name es una categoría abierta, que no depende de la edad.
+func (t Table) IsOpen(name string) bool {
+	for _, c := range t {
+		if c.Open && c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// DependsOnAge indica si name es una categoría con límites de edad, que solo
+// se puede verificar con la fecha de nacimiento.
+func (t Table) DependsOnAge(name string) bool {
+	for _, c := range t {
+		if !c.Open && c.Name == name && (c.MinAge > 0 || c.MaxAge > 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// Names devuelve los nombres de las categorías, sin repetir.
+func (t Table) Names() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, c := range t {
+		if !seen[c.Name] {
+			seen[c.Name] = true
+			names = append(names, c.Name)
+		}
+	}
+	return names
+}
+
+// AgeOn devuelve los años cumplidos el día on por alguien nacido en birth.
+// Quien nació un 29 de febrero cumple años el 1 de marzo en años no bisiestos.
+func AgeOn(birth, on time.Time) int {
+	age := on.Year() - birth.Year()
+	if on.Month() < birth.Month() || on.Month() == birth.Month() && on.Day() < birth.Day() {
+		age--
+	}
+	return age
+}
@@ -0,0 +1,93 @@
+package category
+
+import (
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestAgeOn(t *testing.T) {
+	tests := []struct {
+		birth, on time.Time
+		want      int
+	}{
+		{date(2000, 5, 10), date(2026, 5, 10), 26}, // el día del cumpleaños
+		{date(2000, 5, 11), date(2026, 5, 10), 25}, // un día antes
+		{date(2000, 12, 31), date(2026, 1, 1), 25},
+		{date(2000, 1, 1), date(2026, 12, 31), 26},
+		{date(2004, 2, 29), date(2026, 2, 28), 21}, // sin 29 de febrero, cumple el 1 de marzo
+		{date(2004, 2, 29), date(2026, 3, 1), 22},
+		{date(2004, 2, 29), date(2028, 2, 29), 24},
+		{date(2026, 5, 10), date(2026, 5, 10), 0},
+	}
+	for _, tt := range tests {
+		if got := AgeOn(tt.birth, tt.on); got != tt.want {
+			t.Errorf("AgeOn(%s, %s) = %d, se esperaba %d", tt.birth.Format(time.DateOnly), tt.on.Format(time.DateOnly), got, tt.want)
+		}
+	}
+}
+
+// table imita las categorías del esquema por defecto.
+var table = Table{
+	{Name: "Juvenil", MinAge: 17, MaxAge: 18},
+	{Name: "Elite", MinAge: 19, MaxAge: 29},
+	{Name: "Master B", MinAge: 40, Sexo: "F"},
+	{Name: "Master B", MinAge: 40, MaxAge: 49},
+	{Name: "Master C", MinAge: 50},
+	{Name: "Aficionado", Open: true},
+	{Name: "Tándem", Sexo: "M"},
+}
+
+func TestAssign(t *testing.T) {
+	tests := []struct {
+		age  int
+		sexo string
+		want string // vacío si no hay categoría
+	}{
+		{17, "M", "Juvenil"},
+		{18, "F", "Juvenil"},
+		{19, "F", "Elite"},
+		{29, "M", "Elite"},
+		{45, "M", "Master B"},
+		{55, "F", "Master B"}, // la rama femenil no tiene límite superior
+		{55, "M", "Master C"},
+		{16, "F", ""}, // ninguna con límites; la abierta no se asigna
+		{35, "M", "Tándem"},
+	}
+	for _, tt := range tests {
+		got, ok := table.Assign(tt.age, tt.sexo)
+		if tt.want == "" {
+			if ok {
+				t.Errorf("Assign(%d, %q) = %q, no se esperaba categoría", tt.age, tt.sexo, got.Name)
+			}
+			continue
+		}
+		if !ok || got.Name != tt.want {
+			t.Errorf("Assign(%d, %q) = %q, %v; se esperaba %q", tt.age, tt.sexo, got.Name, ok, tt.want)
+		}
+	}
+}
+
+func TestOpenAndAgeBound(t *testing.T) {
+	tests := []struct {
+		name               string
+		open, dependsOnAge bool
+	}{
+		{"Elite", false, true},
+		{"Master C", false, true},
+		{"Aficionado", true, false},
+		{"Tándem", false, false},
+		{"Desconocida", false, false},
+	}
+	for _, tt := range tests {
+		if got := table.IsOpen(tt.name); got != tt.open {
+			t.Errorf("IsOpen(%q) = %v", tt.name, got)
+		}
+		if got := table.DependsOnAge(tt.name); got != tt.dependsOnAge {
+			t.Errorf("DependsOnAge(%q) = %v", tt.name, got)
+		}
+	}
+}
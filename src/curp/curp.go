@@ -0,0 +1,97 @@
+// Package curp valida la Clave Única de Registro de Población (CURP): su
+// estructura, la entidad de nacimiento, la fecha y el dígito verificador.
+package curp
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Problem identifica por qué una CURP no es válida.
+type Problem int
+
+const (
+	OK Problem = iota
+	InvalidFormat
+	InvalidState
+	InvalidDate
+	InvalidCheckDigit
+)
+
+// CURP es una clave ya validada, con los datos que codifica.
+type CURP struct {
+	Value     string
+	BirthDate time.Time
+	Sex       byte // 'H' (hombre), 'M' (mujer) o 'X' (no binario)
+	State     string
+}
+
+// Sexo devuelve el sexo de la clave con los valores del DSL ("M" masculino,
+// "F" femenino), o "" si la clave no distingue entre ambos.
+func (c CURP) Sexo() string {
+	switch c.Sex {
+	case 'H':
+		return "M"
+	case 'M':
+		return "F"
+	default:
+		return ""
+	}
+}
+
+var pattern = regexp.MustCompile(`^[A-Z][AEIOUX][A-Z]{2}[0-9]{6}[HMX][A-Z]{2}[B-DF-HJ-NP-TV-Z]{3}[A-Z0-9][0-9]$`)
+
+// states son las claves de entidad federativa; NE es "nacido en el extranjero".
+var states = map[string]bool{
+	"AS": true, "BC": true, "BS": true, "CC": true, "CL": true, "CM": true,
+	"CS": true, "CH": true, "DF": true, "DG": true, "GT": true, "GR": true,
+	"HG": true, "JC": true, "MC": true, "MN": true, "MS": true, "NT": true,
+	"NL": true, "OC": true, "PL": true, "QT": true, "QR": true, "SP": true,
+	"SL": true, "SR": true, "TC": true, "TS": true, "TL": true, "VZ": true,
+	"YN": true, "ZS": true, "NE": true,
+}
+
+// Parse valida una CURP; las minúsculas se aceptan y se convierten.
+func Parse(s string) (CURP, Problem) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	if !pattern.MatchString(value) {
+		return CURP{}, InvalidFormat
+	}
+	if !states[value[11:13]] {
+		return CURP{}, InvalidState
+	}
+
+	// El carácter 17 distingue el siglo: dígito antes de 2000, letra después.
+	century := "19"
+	if value[16] >= 'A' && value[16] <= 'Z' {
+		century = "20"
+	}
+	birth, err := time.Parse("20060102", century+value[4:10])
+	if err != nil {
+		return CURP{}, InvalidDate
+	}
+
+	if CheckDigit(value[:17]) != value[17] {
+		return CURP{}, InvalidCheckDigit
+	}
+	return CURP{Value: value, BirthDate: birth, Sex: value[10], State: value[11:13]}, OK
+}
+
+// alphabet da a cada carácter su valor (su posición) en el cálculo del
+// dígito verificador.
+var alphabet = []rune("0123456789ABCDEFGHIJKLMNÑOPQRSTUVWXYZ")
+
+// CheckDigit calcula el dígito verificador de los primeros 17 caracteres.
+func CheckDigit(first17 string) byte {
+	sum := 0
+	for i, r := range []rune(first17) {
+		for value, c := range alphabet {
+			if c == r {
+				sum += value * (18 - i)
+				break
+			}
+		}
+	}
+	return byte('0' + (10-sum%10)%10)
+}
@@ -0,0 +1,66 @@
+package curp
+
+import (
+	"testing"
+	"time"
+)
+
+// example es la CURP de ejemplo que publica RENAPO.
+const example = "HEGG560427MVZRRL04"
+
+func TestParse(t *testing.T) {
+	c, problem := Parse(" hegg560427mvzrrl04 ")
+	if problem != OK {
+		t.Fatalf("Parse(%q) = %v, se esperaba OK", example, problem)
+	}
+	if c.Value != example || c.State != "VZ" || c.Sexo() != "F" {
+		t.Errorf("Parse(%q) = %+v", example, c)
+	}
+	if want := time.Date(1956, 4, 27, 0, 0, 0, 0, time.UTC); !c.BirthDate.Equal(want) {
+		t.Errorf("BirthDate = %v, se esperaba %v", c.BirthDate, want)
+	}
+}
+
+// withCheckDigit completa los primeros 17 caracteres con su dígito verificador.
+func withCheckDigit(first17 string) string {
+	return first17 + string(CheckDigit(first17))
+}
+
+func TestCheckDigit(t *testing.T) {
+	tests := []struct {
+		first17 string
+		want    byte
+	}{
+		{example[:17], '4'},
+		{"AAAA000101HDFAAA0", '3'},
+		{"ZZZZ991231MNEZZZ9", '7'},
+		{"MUÑO800101HDFXXX0", '1'}, // la Ñ vale 24, entre la N y la O
+	}
+	for _, tt := range tests {
+		if got := CheckDigit(tt.first17); got != tt.want {
+			t.Errorf("CheckDigit(%q) = %c, se esperaba %c", tt.first17, got, tt.want)
+		}
+	}
+}
+
+func TestParseProblems(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  Problem
+	}{
+		{"dígito verificador incorrecto", "HEGG560427MVZRRL05", InvalidCheckDigit},
+		{"un carácter cambiado", "HEGG560427MVZRRL14", InvalidCheckDigit},
+		{"demasiado corta", "HEGG560427MVZRRL0", InvalidFormat},
+		{"sexo desconocido", "HEGG560427QVZRRL04", InvalidFormat},
+		{"entidad desconocida", withCheckDigit("HEGG560427MXXRRL0"), InvalidState},
+		{"fecha imposible", withCheckDigit("HEGG560230MVZRRL0"), InvalidDate},
+		{"nacido después de 2000", withCheckDigit("HEGG050427MVZRRLA"), OK},
+		{"nacido en el extranjero", withCheckDigit("HEGG560427HNERRL0"), OK},
+	}
+	for _, tt := range tests {
+		if _, got := Parse(tt.value); got != tt.want {
+			t.Errorf("%s: Parse(%q) = %v, se esperaba %v", tt.name, tt.value, got, tt.want)
+		}
+	}
+}
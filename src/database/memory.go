@@ -0,0 +1,274 @@
+package database
+
+import (
+	"compilerciclista/src/models"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryRepository es un ParticipantRepository que vive en memoria, para
+// pruebas y demostraciones sin base de datos. Es seguro para uso concurrente:
+// las escrituras se serializan y cada transacción trabaja sobre una copia
+// que solo reemplaza al estado si se confirma.
+type MemoryRepository struct {
+	txMu  sync.Mutex // una transacción a la vez
+	mu    sync.Mutex // protege state
+	state *memoryState
+}
+
+// NewMemoryRepository crea un repositorio vacío.
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{state: &memoryState{sequences: make(map[sequenceKey]int64)}}
+}
+
+type sequenceKey struct {
+	evento, prefijo string
+}
+
+type memoryState struct {
+	participants []models.Participant
+	audit        []models.AuditEntry
+	users        []models.User
+	sequences    map[sequenceKey]int64
+	lastID       int64
+}
+
+func (s *memoryState) clone() *memoryState {
+	c := &memoryState{
+		participants: append([]models.Participant(nil), s.participants...),
+		audit:        append([]models.AuditEntry(nil), s.audit...),
+		users:        append([]models.User(nil), s.users...),
+		sequences:    make(map[sequenceKey]int64, len(s.sequences)),
+		lastID:       s.lastID,
+	}
+	for k, v := range s.sequences {
+		c.sequences[k] = v
+	}
+	return c
+}
+
+// snapshot devuelve el estado confirmado, que no se modifica en su lugar.
+func (r *MemoryRepository) snapshot() *memoryState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.state
+}
+
+func (r *MemoryRepository) NextSequence(evento, prefijo string) (int64, error) {
+	var n int64
+	err := r.WithTx(func(repo ParticipantRepository) (err error) {
+		n, err = repo.NextSequence(evento, prefijo)
+		return err
+	})
+	return n, err
+}
+
+func (r *MemoryRepository) Create(p models.Participant) (int64, error) {
+	var id int64
+	err := r.WithTx(func(repo ParticipantRepository) (err error) {
+		id, err = repo.Create(p)
+		return err
+	})
+	return id, err
+}
+
+func (r *MemoryRepository) Update(p models.Participant) error {
+	return r.WithTx(func(repo ParticipantRepository) error { return repo.Update(p) })
+}
+
+func (r *MemoryRepository) RecordAudit(entry models.AuditEntry) error {
+	return r.WithTx(func(repo ParticipantRepository) error { return repo.RecordAudit(entry) })
+}
+
+func (r *MemoryRepository) History(participantID int64) ([]models.AuditEntry, error) {
+	return (&memoryTx{state: r.snapshot()}).History(participantID)
+}
+
+func (r *MemoryRepository) FindByCode(evento, code string) (models.Participant, error) {
+	return (&memoryTx{state: r.snapshot()}).FindByCode(evento, code)
+}
+
+func (r *MemoryRepository) FindByID(id int64) (models.Participant, error) {
+	return (&memoryTx{state: r.snapshot()}).FindByID(id)
+}
+
+func (r *MemoryRepository) FindByCodeForUpdate(evento, code string) (models.Participant, error) {
+	return r.FindByCode(evento, code)
+}
+
+func (r *MemoryRepository) FindByIDForUpdate(id int64) (models.Participant, error) {
+	return r.FindByID(id)
+}
+
+func (r *MemoryRepository) List(filter ParticipantFilter) ([]models.Participant, int64, error) {
+	return (&memoryTx{state: r.snapshot()}).List(filter)
+}
+
+func (r *MemoryRepository) WithTx(fn func(repo ParticipantRepository) error) error {
+	r.txMu.Lock()
+	defer r.txMu.Unlock()
+
+	tx := &memoryTx{state: r.snapshot().clone()}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.state = tx.state
+	r.mu.Unlock()
+	return nil
+}
+
+// memoryTx es el repositorio que ve fn dentro de WithTx.
+type memoryTx struct {
+	state *memoryState
+}
+
+func (t *memoryTx) NextSequence(evento, prefijo string) (int64, error) {
+	key := sequenceKey{evento, prefijo}
+	t.state.sequences[key]++
+	return t.state.sequences[key], nil
+}
+
+func (t *memoryTx) Create(p models.Participant) (int64, error) {
+	if err := t.checkUnique(p); err != nil {
+		return 0, err
+	}
+	t.state.lastID++
+	p.ID = t.state.lastID
+	p.Estado = estadoOrActivo(p.Estado)
+	now := time.Now()
+	p.CreatedAt = &now
+	t.state.participants = append(t.state.participants, p)
+	return p.ID, nil
+}
+
+func (t *memoryTx) Update(p models.Participant) error {
+	if err := t.checkUnique(p); err != nil {
+		return err
+	}
+	for i, existing := range t.state.participants {
+		if existing.ID == p.ID {
+			p.Estado = estadoOrActivo(p.Estado)
+			p.CreatedAt = existing.CreatedAt
+			t.state.participants[i] = p
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (t *memoryTx) RecordAudit(entry models.AuditEntry) error {
+	now := time.Now()
+	entry.ID = int64(len(t.state.audit)) + 1
+	entry.CreatedAt = &now
+	t.state.audit = append(t.state.audit, entry)
+	return nil
+}
+
+func (t *memoryTx) History(participantID int64) ([]models.AuditEntry, error) {
+	entries := []models.AuditEntry{}
+	for _, entry := range t.state.audit {
+		if entry.ParticipantID == participantID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// checkUnique aplica las mismas claves únicas que las tablas SQL, todas
+// dentro del evento, sin comparar al participante consigo mismo.
+func (t *memoryTx) checkUnique(p models.Participant) error {
+	for _, existing := range t.state.participants {
+		if p.ID != 0 && existing.ID == p.ID || existing.Evento != p.Evento {
+			continue
+		}
+		switch {
+		case strings.EqualFold(existing.Email, p.Email):
+			return &DuplicateError{Field: "email"}
+		case p.CURP != "" && existing.CURP == p.CURP:
+			return &DuplicateError{Field: "curp"}
+		case existing.ParticipantCode == p.ParticipantCode:
+			return &DuplicateError{Field: "participant_code"}
+		}
+	}
+	return nil
+}
+
+func (t *memoryTx) FindByCode(evento, code string) (models.Participant, error) {
+	for _, p := range t.state.participants {
+		if p.Evento == evento && p.ParticipantCode == code {
+			return p, nil
+		}
+	}
+	return models.Participant{}, ErrNotFound
+}
+
+func (t *memoryTx) FindByID(id int64) (models.Participant, error) {
+	for _, p := range t.state.participants {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return models.Participant{}, ErrNotFound
+}
+
+// FindByCodeForUpdate no necesita bloquear: WithTx ya ejecuta una
+// transacción a la vez.
+func (t *memoryTx) FindByCodeForUpdate(evento, code string) (models.Participant, error) {
+	return t.FindByCode(evento, code)
+}
+
+func (t *memoryTx) FindByIDForUpdate(id int64) (models.Participant, error) {
+	return t.FindByID(id)
+}
+
+func (t *memoryTx) List(filter ParticipantFilter) ([]models.Participant, int64, error) {
+	words := SearchWords(filter.Search)
+	var matches []models.Participant
+	for _, p := range t.state.participants {
+		if memoryMatches(p, filter, words) {
+			matches = append(matches, p)
+		}
+	}
+
+	total := int64(len(matches))
+	page := []models.Participant{}
+	if filter.Offset < len(matches) {
+		matches = matches[filter.Offset:]
+		if filter.Limit < len(matches) {
+			matches = matches[:filter.Limit]
+		}
+		page = append(page, matches...)
+	}
+	return page, total, nil
+}
+
+// foldAccents quita los acentos, como hacen la intercalación de MySQL y el
+// índice de texto completo de SQLite al buscar.
+var foldAccents = strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n")
+
+// memoryMatches aplica el filtro como lo harían las consultas SQL.
+func memoryMatches(p models.Participant, filter ParticipantFilter, words []string) bool {
+	switch {
+	case p.Evento != filter.Evento,
+		p.Estado != estadoOrActivo(filter.Estado),
+		filter.Categoria != "" && p.Categoria != filter.Categoria,
+		filter.Sexo != "" && p.Sexo != filter.Sexo,
+		filter.PagoRealizado != nil && p.PagoRealizado != *filter.PagoRealizado,
+		filter.CreatedFrom != nil && p.CreatedAt.Before(*filter.CreatedFrom),
+		filter.CreatedTo != nil && !p.CreatedAt.Before(*filter.CreatedTo):
+		return false
+	}
+	text := foldAccents.Replace(strings.ToLower(strings.Join([]string{p.Nombre, p.ApellidoPaterno, p.ApellidoMaterno, p.Email}, " ")))
+	for _, word := range words {
+		if !strings.Contains(text, foldAccents.Replace(word)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *memoryTx) WithTx(fn func(repo ParticipantRepository) error) error {
+	return fn(t)
+}
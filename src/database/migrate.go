@@ -0,0 +1,358 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Las migraciones de cada motor viven en migrations/<motor>/ con nombres
+// NNNN_descripcion.up.sql y NNNN_descripcion.down.sql.
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+// Migration es un cambio versionado del esquema de la base de datos.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus indica si una migración ya se aplicó y cuándo.
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+const migrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INT NOT NULL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+)`
+
+// Migrations devuelve las migraciones del motor, ordenadas por versión.
+func Migrations(driver string) ([]Migration, error) {
+	dir := path.Join("migrations", driver)
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no hay migraciones para el motor '%s'", driver)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		name := entry.Name()
+		base, direction, ok := strings.Cut(strings.TrimSuffix(name, ".sql"), ".")
+		number, description, found := strings.Cut(base, "_")
+		version, err := strconv.Atoi(number)
+		if !ok || !found || err != nil || direction != "up" && direction != "down" {
+			return nil, fmt.Errorf("nombre de migración inválido '%s'", name)
+		}
+		content, err := fs.ReadFile(migrationFiles, path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &Migration{Version: version, Name: description}
+			byVersion[version] = m
+		}
+		if direction == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("a la migración %04d_%s le falta el archivo .up.sql", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// MigrateUp aplica, en orden, las migraciones pendientes y devuelve las que
+// aplicó. Cada una se registra en schema_migrations al terminar. Una base de
+// datos creada con el script manual se adopta primero con AdoptLegacySchema.
+func MigrateUp(db *sql.DB, driver string) ([]Migration, error) {
+	if _, err := AdoptLegacySchema(db, driver); err != nil {
+		return nil, err
+	}
+	statuses, err := MigrationStatuses(db, driver)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, status := range statuses {
+		if status.AppliedAt != nil {
+			continue
+		}
+		m := status.Migration
+		if err := runMigration(db, driver, m.Up, func(tx *sql.Tx) error {
+			_, err := tx.Exec("INSERT INTO schema_migrations (version, name) VALUES (?, ?)", m.Version, m.Name)
+			return err
+		}); err != nil {
+			return applied, fmt.Errorf("falló la migración %04d_%s: %w", m.Version, m.Name, err)
+		}
+		applied = append(applied, m)
+	}
+	return applied, nil
+}
+
+// MigrateDown revierte las últimas steps migraciones aplicadas y devuelve las
+// que revirtió.
+func MigrateDown(db *sql.DB, driver string, steps int) ([]Migration, error) {
+	statuses, err := MigrationStatuses(db, driver)
+	if err != nil {
+		return nil, err
+	}
+
+	var reverted []Migration
+	for i := len(statuses) - 1; i >= 0 && len(reverted) < steps; i-- {
+		if statuses[i].AppliedAt == nil {
+			continue
+		}
+		m := statuses[i].Migration
+		if m.Down == "" {
+			return reverted, fmt.Errorf("la migración %04d_%s no se puede revertir: no tiene archivo .down.sql", m.Version, m.Name)
+		}
+		if err := runMigration(db, driver, m.Down, func(tx *sql.Tx) error {
+			_, err := tx.Exec("DELETE FROM schema_migrations WHERE version = ?", m.Version)
+			return err
+		}); err != nil {
+			return reverted, fmt.Errorf("falló la reversión de %04d_%s: %w", m.Version, m.Name, err)
+		}
+		reverted = append(reverted, m)
+	}
+	return reverted, nil
+}
+
+// MarkApplied registra como aplicadas las migraciones hasta version sin
+// ejecutarlas, para adoptar una base de datos creada a mano con el script.
+func MarkApplied(db *sql.DB, driver string, version int) error {
+	statuses, err := MigrationStatuses(db, driver)
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		if status.Version > version || status.AppliedAt != nil {
+			continue
+		}
+		if _, err := db.Exec("INSERT INTO schema_migrations (version, name) VALUES (?, ?)", status.Version, status.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// legacyVersion describe el esquema que dejaba una versión del script manual
+// (src/scriptMySQL/scriptSQL.sql, o sqlite_schema.sql en SQLite) mediante
+// las columnas "tabla.columna" que ya existían con ella.
+type legacyVersion struct {
+	version int
+	columns []string
+}
+
+// legacySchemas son, por motor y en orden, las versiones del script manual.
+// Las migraciones posteriores a estas nunca se aplicaron a mano.
+var legacySchemas = map[string][]legacyVersion{
+	DriverMySQL: {
+		{1, []string{"participantes.id"}},
+		{2, []string{"participantes.participant_code"}},
+		{3, []string{"participantes.fecha_nacimiento"}},
+		{4, []string{"participantes.curp"}},
+		{5, []string{"participantes.evento", "secuencias_codigo.ultimo"}},
+	},
+	DriverSQLite: {
+		{1, []string{"participantes.evento", "participantes.curp", "secuencias_codigo.ultimo"}},
+	},
+}
+
+// AdoptLegacySchema reconoce una base de datos creada con el script manual
+// (con la tabla participantes pero sin migraciones registradas), deduce a qué
+// migración equivale por las columnas que tiene y la marca como aplicada con
+// MarkApplied. Devuelve esa versión, o 0 si no había nada que adoptar.
+func AdoptLegacySchema(db *sql.DB, driver string) (int, error) {
+	if _, err := db.Exec(migrationsTable); err != nil {
+		return 0, fmt.Errorf("no se pudo crear la tabla schema_migrations: %w", err)
+	}
+	var recorded int
+	if err := db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&recorded); err != nil {
+		return 0, err
+	}
+	if recorded > 0 {
+		return 0, nil
+	}
+
+	version := 0
+	for _, legacy := range legacySchemas[driver] {
+		if !hasColumns(db, legacy.columns) {
+			break
+		}
+		version = legacy.version
+	}
+	if version == 0 {
+		return 0, nil
+	}
+	if err := MarkApplied(db, driver, version); err != nil {
+		return 0, fmt.Errorf("no se pudo adoptar el esquema existente: %w", err)
+	}
+	log.Printf("Esquema creado con el script manual adoptado como la migración %04d.", version)
+	return version, nil
+}
+
+// hasColumns indica si existen todas las columnas "tabla.columna". La
+// consulta sin filas funciona igual en MySQL y en SQLite.
+func hasColumns(db *sql.DB, columns []string) bool {
+	for _, column := range columns {
+		table, name, _ := strings.Cut(column, ".")
+		rows, err := db.Query("SELECT " + name + " FROM " + table + " LIMIT 0")
+		if err != nil {
+			return false
+		}
+		rows.Close()
+	}
+	return true
+}
+
+// MigrationStatuses devuelve todas las migraciones del motor indicando cuáles
+// ya se aplicaron en db.
+func MigrationStatuses(db *sql.DB, driver string) ([]MigrationStatus, error) {
+	migrations, err := Migrations(driver)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(migrationsTable); err != nil {
+		return nil, fmt.Errorf("no se pudo crear la tabla schema_migrations: %w", err)
+	}
+
+	rows, err := db.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, len(migrations))
+	for i, m := range migrations {
+		statuses[i].Migration = m
+		if at, ok := applied[m.Version]; ok {
+			statuses[i].AppliedAt = &at
+		}
+	}
+	return statuses, nil
+}
+
+// runMigration ejecuta las sentencias de script y record en una transacción.
+// MySQL confirma implícitamente cada sentencia DDL, así que allí una
+// migración que falle a la mitad puede requerir corrección manual.
+//
+// En SQLite las claves foráneas se desactivan mientras corre la migración,
+// porque reconstruir una tabla referenciada exige borrarla, y se comprueban
+// antes de confirmar. El PRAGMA no tiene efecto dentro de una transacción,
+// así que se fija en la conexión que luego la abre.
+func runMigration(db *sql.DB, driver, script string, record func(tx *sql.Tx) error) error {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if driver == DriverSQLite {
+		if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+			return err
+		}
+		defer conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, statement := range splitStatements(script) {
+		if _, err := tx.Exec(statement); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if driver == DriverSQLite {
+		if err := checkForeignKeys(tx); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if err := record(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// checkForeignKeys falla si alguna fila de SQLite apunta a otra que no existe.
+func checkForeignKeys(tx *sql.Tx) error {
+	rows, err := tx.Query("PRAGMA foreign_key_check")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		var table, parent string
+		var rowID, fkID sql.NullInt64
+		if err := rows.Scan(&table, &rowID, &parent, &fkID); err != nil {
+			return err
+		}
+		return fmt.Errorf("la fila %d de %s apunta a una fila de %s que no existe", rowID.Int64, table, parent)
+	}
+	return rows.Err()
+}
+
+// splitStatements separa un script en sentencias terminadas en ';' al final
+// de una línea, descartando las líneas de comentario '--'. El cuerpo de un
+// CREATE TRIGGER llega hasta la línea 'END;'.
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		inTrigger := strings.HasPrefix(strings.ToUpper(strings.TrimSpace(current.String())), "CREATE TRIGGER")
+		if inTrigger && strings.EqualFold(trimmed, "END;") || !inTrigger && strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
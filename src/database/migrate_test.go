@@ -0,0 +1,201 @@
+package database
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"compilerciclista/src/models"
+)
+
+// Esquema de participantes que creaba la primera versión del script manual
+// de MySQL, escrito para SQLite.
+const legacyParticipantes = `CREATE TABLE participantes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    nombre VARCHAR(255) NOT NULL,
+    apellido_paterno VARCHAR(255) NOT NULL,
+    apellido_materno VARCHAR(255),
+    email VARCHAR(255) NOT NULL UNIQUE,
+    sexo CHAR(1) NOT NULL,
+    categoria VARCHAR(100) NOT NULL,
+    pago_realizado BOOLEAN DEFAULT false,
+    ine_path VARCHAR(255),
+    comprobante_pago_path VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+)`
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := Open(DriverSQLite, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func execAll(t *testing.T, db *sql.DB, statements ...string) {
+	t.Helper()
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("%s: %v", statement, err)
+		}
+	}
+}
+
+func TestAdoptLegacyMySQLScript(t *testing.T) {
+	tests := []struct {
+		name       string
+		statements []string
+		want       int
+	}{
+		{"base de datos vacía", nil, 0},
+		{"tabla original", []string{legacyParticipantes}, 1},
+		{"con participant_code", []string{legacyParticipantes,
+			"ALTER TABLE participantes ADD COLUMN participant_code VARCHAR(50)"}, 2},
+		{"con fecha_nacimiento", []string{legacyParticipantes,
+			"ALTER TABLE participantes ADD COLUMN participant_code VARCHAR(50)",
+			"ALTER TABLE participantes ADD COLUMN fecha_nacimiento DATE"}, 3},
+		{"con curp", []string{legacyParticipantes,
+			"ALTER TABLE participantes ADD COLUMN participant_code VARCHAR(50)",
+			"ALTER TABLE participantes ADD COLUMN fecha_nacimiento DATE",
+			"ALTER TABLE participantes ADD COLUMN curp CHAR(18)"}, 4},
+		{"con secuencias", []string{legacyParticipantes,
+			"ALTER TABLE participantes ADD COLUMN participant_code VARCHAR(50)",
+			"ALTER TABLE participantes ADD COLUMN fecha_nacimiento DATE",
+			"ALTER TABLE participantes ADD COLUMN curp CHAR(18)",
+			"ALTER TABLE participantes ADD COLUMN evento VARCHAR(255)",
+			"CREATE TABLE secuencias_codigo (evento VARCHAR(255), prefijo VARCHAR(10), ultimo INT)"}, 5},
+		{"evento sin secuencias", []string{legacyParticipantes,
+			"ALTER TABLE participantes ADD COLUMN participant_code VARCHAR(50)",
+			"ALTER TABLE participantes ADD COLUMN fecha_nacimiento DATE",
+			"ALTER TABLE participantes ADD COLUMN curp CHAR(18)",
+			"ALTER TABLE participantes ADD COLUMN evento VARCHAR(255)"}, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t)
+			execAll(t, db, tt.statements...)
+
+			got, err := AdoptLegacySchema(db, DriverMySQL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("versión adoptada = %d, se esperaba %d", got, tt.want)
+			}
+			statuses, err := MigrationStatuses(db, DriverMySQL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range statuses {
+				if applied := s.AppliedAt != nil; applied != (s.Version <= tt.want) {
+					t.Errorf("migración %04d aplicada = %v", s.Version, applied)
+				}
+			}
+
+			// Una vez registradas las migraciones no se vuelve a adoptar
+			if again, err := AdoptLegacySchema(db, DriverMySQL); err != nil || again != 0 {
+				t.Errorf("segunda adopción = %d, %v", again, err)
+			}
+		})
+	}
+}
+
+func TestMigrateUpFromLegacySQLiteSchema(t *testing.T) {
+	db := openTestDB(t)
+	// sqlite_schema.sql, anterior a las migraciones
+	execAll(t, db, `CREATE TABLE participantes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    participant_code VARCHAR(50) NOT NULL,
+    evento VARCHAR(255) NOT NULL DEFAULT 'Competencia Ciclista',
+    nombre VARCHAR(255) NOT NULL,
+    apellido_paterno VARCHAR(255) NOT NULL,
+    apellido_materno VARCHAR(255),
+    email VARCHAR(255) NOT NULL UNIQUE COLLATE NOCASE,
+    sexo CHAR(1) NOT NULL,
+    categoria VARCHAR(100) NOT NULL,
+    pago_realizado BOOLEAN DEFAULT false,
+    ine_path VARCHAR(255),
+    comprobante_pago_path VARCHAR(255),
+    fecha_nacimiento DATE NULL,
+    curp CHAR(18) NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (evento, participant_code)
+)`, `CREATE TABLE secuencias_codigo (
+    evento VARCHAR(255) NOT NULL,
+    prefijo VARCHAR(10) NOT NULL,
+    ultimo INT NOT NULL,
+    PRIMARY KEY (evento, prefijo)
+)`, `INSERT INTO participantes (participant_code, nombre, apellido_paterno, email, sexo, categoria)
+VALUES ('ELI-001', 'Ana', 'Pérez', 'ana@unam.mx', 'F', 'Elite')`)
+
+	if _, err := MigrateUp(db, DriverSQLite); err != nil {
+		t.Fatalf("MigrateUp sobre el esquema anterior: %v", err)
+	}
+	statuses, err := MigrationStatuses(db, DriverSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range statuses {
+		if s.AppliedAt == nil {
+			t.Errorf("migración %04d_%s pendiente", s.Version, s.Name)
+		}
+	}
+
+	p, err := NewSQLiteRepository(db).FindByCode("Competencia Ciclista", "ELI-001")
+	if err != nil {
+		t.Fatalf("el participante existente no se conservó: %v", err)
+	}
+	if p.Nombre != "Ana" || p.Estado != "activo" {
+		t.Errorf("participante = %+v", p)
+	}
+}
+
+func TestMigrateDownAndUpAgain(t *testing.T) {
+	db := openTestDB(t)
+	applied, err := MigrateUp(db, DriverSQLite)
+	if err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+	reverted, err := MigrateDown(db, DriverSQLite, len(applied))
+	if err != nil {
+		t.Fatalf("MigrateDown: %v", err)
+	}
+	if len(reverted) != len(applied) {
+		t.Fatalf("se revirtieron %d de %d migraciones", len(reverted), len(applied))
+	}
+	if _, err := MigrateUp(db, DriverSQLite); err != nil {
+		t.Fatalf("MigrateUp tras revertir todo: %v", err)
+	}
+}
+
+func TestRebuildingParticipantsKeepsAudit(t *testing.T) {
+	db := openTestDB(t)
+	if _, err := MigrateUp(db, DriverSQLite); err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+	repo := NewSQLiteRepository(db)
+	id, err := repo.Create(participant("Gran Fondo", "ELI-001", "ana@unam.mx", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.RecordAudit(models.AuditEntry{ParticipantID: id, Action: "alta", Actor: "mesa"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 0007 reconstruye participantes, a la que apunta la auditoría
+	if _, err := MigrateDown(db, DriverSQLite, 1); err != nil {
+		t.Fatalf("MigrateDown con auditoría: %v", err)
+	}
+	if _, err := MigrateUp(db, DriverSQLite); err != nil {
+		t.Fatalf("MigrateUp con auditoría: %v", err)
+	}
+
+	if history, err := repo.History(id); err != nil || len(history) != 1 {
+		t.Errorf("History = %v, %v; se esperaba la entrada original", history, err)
+	}
+	if err := repo.RecordAudit(models.AuditEntry{ParticipantID: id + 100, Action: "alta", Actor: "mesa"}); err == nil {
+		t.Error("las claves foráneas quedaron desactivadas tras migrar")
+	}
+}
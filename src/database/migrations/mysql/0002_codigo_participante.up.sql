@@ -0,0 +1,3 @@
+ALTER TABLE participantes
+ADD COLUMN participant_code VARCHAR(50) NOT NULL UNIQUE,
+ADD INDEX (participant_code);
@@ -0,0 +1,3 @@
+-- Fecha de nacimiento, con la que se asigna la categoría por edad
+ALTER TABLE participantes
+ADD COLUMN fecha_nacimiento DATE NULL;
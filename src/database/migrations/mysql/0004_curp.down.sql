@@ -0,0 +1,2 @@
+ALTER TABLE participantes
+DROP COLUMN curp;
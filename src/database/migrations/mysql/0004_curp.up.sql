@@ -0,0 +1,4 @@
+-- CURP opcional; al ser UNIQUE detecta a quien se registra dos veces con otro
+-- email (MySQL permite varios NULL en una columna UNIQUE)
+ALTER TABLE participantes
+ADD COLUMN curp CHAR(18) NULL UNIQUE;
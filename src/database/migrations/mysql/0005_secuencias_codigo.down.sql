@@ -0,0 +1,6 @@
+ALTER TABLE participantes
+DROP INDEX participant_code_evento,
+ADD UNIQUE INDEX participant_code (participant_code),
+DROP COLUMN evento;
+
+DROP TABLE secuencias_codigo;
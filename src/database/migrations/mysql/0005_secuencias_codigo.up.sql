@@ -0,0 +1,19 @@
+-- Los códigos de participante se numeran por categoría dentro de cada evento,
+-- con un contador que nunca retrocede
+CREATE TABLE secuencias_codigo (
+    evento VARCHAR(255) NOT NULL,
+    prefijo VARCHAR(10) NOT NULL,
+    ultimo INT NOT NULL,
+    PRIMARY KEY (evento, prefijo)
+);
+
+ALTER TABLE participantes
+ADD COLUMN evento VARCHAR(255) NOT NULL DEFAULT 'Competencia Ciclista' AFTER participant_code,
+DROP INDEX participant_code,
+ADD UNIQUE INDEX participant_code_evento (evento, participant_code);
+
+-- Si ya había participantes, continuar cada secuencia desde el código más alto
+INSERT INTO secuencias_codigo (evento, prefijo, ultimo)
+SELECT evento, SUBSTRING_INDEX(participant_code, '-', 1), MAX(CAST(SUBSTRING_INDEX(participant_code, '-', -1) AS UNSIGNED))
+FROM participantes
+GROUP BY evento, SUBSTRING_INDEX(participant_code, '-', 1);
@@ -0,0 +1,5 @@
+ALTER TABLE participantes
+DROP INDEX participantes_busqueda,
+DROP INDEX participantes_evento_categoria,
+DROP INDEX participantes_evento_sexo,
+DROP INDEX participantes_evento_creacion;
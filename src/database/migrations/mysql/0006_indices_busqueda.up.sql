@@ -0,0 +1,8 @@
+-- Índices para la lista y la búsqueda de participantes (GET /participants)
+ALTER TABLE participantes
+ADD FULLTEXT INDEX participantes_busqueda (nombre, apellido_paterno, apellido_materno, email);
+
+ALTER TABLE participantes
+ADD INDEX participantes_evento_categoria (evento, categoria),
+ADD INDEX participantes_evento_sexo (evento, sexo),
+ADD INDEX participantes_evento_creacion (evento, created_at);
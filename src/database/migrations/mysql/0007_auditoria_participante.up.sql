@@ -0,0 +1,13 @@
+-- Auditoría de participantes: quién corrigió a cada participante con
+-- PATCH /participants/{code} y cómo quedó antes y después del cambio.
+CREATE TABLE auditoria_participante (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    participante_id INT NOT NULL,
+    accion VARCHAR(20) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    antes JSON NULL,
+    despues JSON NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX (participante_id),
+    FOREIGN KEY (participante_id) REFERENCES participantes (id)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE participantes
+DROP INDEX participantes_evento_estado,
+DROP COLUMN estado;
@@ -0,0 +1,4 @@
+DROP TRIGGER auditoria_participante_sin_borrado;
+DROP TRIGGER auditoria_participante_sin_cambios;
+
+ALTER TABLE auditoria_participante DROP COLUMN request_id;
@@ -0,0 +1,12 @@
+-- Cuentas para entrar a la API (POST /login) y su rol: admin, organizador,
+-- mesa_registro, juez o corredor. Las contraseñas se guardan con bcrypt.
+CREATE TABLE usuarios (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    username VARCHAR(100) NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    rol VARCHAR(20) NOT NULL,
+    participante_id INT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY usuarios_username (username),
+    FOREIGN KEY (participante_id) REFERENCES participantes (id)
+);
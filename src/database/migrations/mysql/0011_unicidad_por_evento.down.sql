@@ -0,0 +1,5 @@
+ALTER TABLE participantes
+DROP INDEX participantes_evento_curp,
+DROP INDEX participantes_evento_email,
+ADD UNIQUE INDEX email (email),
+ADD UNIQUE INDEX curp (curp);
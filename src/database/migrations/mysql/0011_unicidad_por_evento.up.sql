@@ -0,0 +1,7 @@
+-- El email y la CURP identifican a un participante dentro de su evento: la
+-- misma persona puede inscribirse en otros eventos
+ALTER TABLE participantes
+DROP INDEX email,
+DROP INDEX curp,
+ADD UNIQUE INDEX participantes_evento_email (evento, email),
+ADD UNIQUE INDEX participantes_evento_curp (evento, curp);
@@ -0,0 +1,2 @@
+DROP TABLE secuencias_codigo;
+DROP TABLE participantes;
@@ -0,0 +1,30 @@
+-- Esquema de SQLite equivalente al de las migraciones 0001 a 0005 de MySQL.
+-- Usa IF NOT EXISTS para adoptar las bases de datos creadas antes de que
+-- hubiera migraciones.
+
+CREATE TABLE IF NOT EXISTS participantes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    participant_code VARCHAR(50) NOT NULL,
+    evento VARCHAR(255) NOT NULL DEFAULT 'Competencia Ciclista',
+    nombre VARCHAR(255) NOT NULL,
+    apellido_paterno VARCHAR(255) NOT NULL,
+    apellido_materno VARCHAR(255),
+    -- NOCASE: igual que la intercalación de MySQL, sin distinguir mayúsculas
+    email VARCHAR(255) NOT NULL UNIQUE COLLATE NOCASE,
+    sexo CHAR(1) NOT NULL,
+    categoria VARCHAR(100) NOT NULL,
+    pago_realizado BOOLEAN DEFAULT false,
+    ine_path VARCHAR(255),
+    comprobante_pago_path VARCHAR(255),
+    fecha_nacimiento DATE NULL,
+    curp CHAR(18) NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (evento, participant_code)
+);
+
+CREATE TABLE IF NOT EXISTS secuencias_codigo (
+    evento VARCHAR(255) NOT NULL,
+    prefijo VARCHAR(10) NOT NULL,
+    ultimo INT NOT NULL,
+    PRIMARY KEY (evento, prefijo)
+);
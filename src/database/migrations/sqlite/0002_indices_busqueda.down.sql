@@ -0,0 +1,7 @@
+DROP TRIGGER participantes_fts_update;
+DROP TRIGGER participantes_fts_delete;
+DROP TRIGGER participantes_fts_insert;
+DROP TABLE participantes_fts;
+DROP INDEX participantes_evento_creacion;
+DROP INDEX participantes_evento_sexo;
+DROP INDEX participantes_evento_categoria;
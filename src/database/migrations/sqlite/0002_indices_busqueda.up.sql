@@ -0,0 +1,31 @@
+-- Índices para la lista y la búsqueda de participantes (GET /participants)
+CREATE INDEX participantes_evento_categoria ON participantes (evento, categoria);
+CREATE INDEX participantes_evento_sexo ON participantes (evento, sexo);
+CREATE INDEX participantes_evento_creacion ON participantes (evento, created_at);
+
+-- Índice de texto completo sobre nombres y email, sin distinguir acentos,
+-- que los triggers mantienen al día
+CREATE VIRTUAL TABLE participantes_fts USING fts5(
+    nombre, apellido_paterno, apellido_materno, email,
+    content = 'participantes', content_rowid = 'id',
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+
+INSERT INTO participantes_fts (participantes_fts) VALUES ('rebuild');
+
+CREATE TRIGGER participantes_fts_insert AFTER INSERT ON participantes BEGIN
+    INSERT INTO participantes_fts (rowid, nombre, apellido_paterno, apellido_materno, email)
+    VALUES (new.id, new.nombre, new.apellido_paterno, new.apellido_materno, new.email);
+END;
+
+CREATE TRIGGER participantes_fts_delete AFTER DELETE ON participantes BEGIN
+    INSERT INTO participantes_fts (participantes_fts, rowid, nombre, apellido_paterno, apellido_materno, email)
+    VALUES ('delete', old.id, old.nombre, old.apellido_paterno, old.apellido_materno, old.email);
+END;
+
+CREATE TRIGGER participantes_fts_update AFTER UPDATE ON participantes BEGIN
+    INSERT INTO participantes_fts (participantes_fts, rowid, nombre, apellido_paterno, apellido_materno, email)
+    VALUES ('delete', old.id, old.nombre, old.apellido_paterno, old.apellido_materno, old.email);
+    INSERT INTO participantes_fts (rowid, nombre, apellido_paterno, apellido_materno, email)
+    VALUES (new.id, new.nombre, new.apellido_paterno, new.apellido_materno, new.email);
+END;
@@ -0,0 +1,13 @@
+-- Auditoría de participantes: quién corrigió a cada participante con
+-- PATCH /participants/{code} y cómo quedó antes y después del cambio.
+CREATE TABLE auditoria_participante (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    participante_id INTEGER NOT NULL REFERENCES participantes (id),
+    accion VARCHAR(20) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    antes TEXT,
+    despues TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX auditoria_participante_participante ON auditoria_participante (participante_id);
@@ -0,0 +1,2 @@
+DROP INDEX participantes_evento_estado;
+ALTER TABLE participantes DROP COLUMN estado;
@@ -0,0 +1,5 @@
+-- Estado de la inscripción: activo, retirado, cancelado o descalificado.
+-- Las bajas conservan la fila y el código, que nunca se vuelve a emitir.
+ALTER TABLE participantes ADD COLUMN estado VARCHAR(20) NOT NULL DEFAULT 'activo';
+
+CREATE INDEX participantes_evento_estado ON participantes (evento, estado);
@@ -0,0 +1,13 @@
+-- La auditoría pasa a ser de solo inserción y registra también el alta, el
+-- pago y la petición HTTP que originó cada entrada.
+ALTER TABLE auditoria_participante ADD COLUMN request_id VARCHAR(64);
+
+CREATE TRIGGER auditoria_participante_sin_cambios BEFORE UPDATE ON auditoria_participante
+BEGIN
+    SELECT RAISE(ABORT, 'La auditoría de participantes no se puede modificar');
+END;
+
+CREATE TRIGGER auditoria_participante_sin_borrado BEFORE DELETE ON auditoria_participante
+BEGIN
+    SELECT RAISE(ABORT, 'La auditoría de participantes no se puede borrar');
+END;
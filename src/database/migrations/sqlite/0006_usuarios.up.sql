@@ -0,0 +1,10 @@
+-- Cuentas para entrar a la API (POST /login) y su rol: admin, organizador,
+-- mesa_registro, juez o corredor. Las contraseñas se guardan con bcrypt.
+CREATE TABLE usuarios (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    username VARCHAR(100) NOT NULL UNIQUE COLLATE NOCASE,
+    password_hash VARCHAR(255) NOT NULL,
+    rol VARCHAR(20) NOT NULL,
+    participante_id INTEGER REFERENCES participantes (id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,56 @@
+-- Vuelve a las claves únicas globales de email y CURP; falla si la misma
+-- persona quedó inscrita en más de un evento.
+CREATE TABLE participantes_globales (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    participant_code VARCHAR(50) NOT NULL,
+    evento VARCHAR(255) NOT NULL DEFAULT 'Competencia Ciclista',
+    nombre VARCHAR(255) NOT NULL,
+    apellido_paterno VARCHAR(255) NOT NULL,
+    apellido_materno VARCHAR(255),
+    -- NOCASE: igual que la intercalación de MySQL, sin distinguir mayúsculas
+    email VARCHAR(255) NOT NULL UNIQUE COLLATE NOCASE,
+    sexo CHAR(1) NOT NULL,
+    categoria VARCHAR(100) NOT NULL,
+    pago_realizado BOOLEAN DEFAULT false,
+    ine_path VARCHAR(255),
+    comprobante_pago_path VARCHAR(255),
+    fecha_nacimiento DATE NULL,
+    curp CHAR(18) NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    estado VARCHAR(20) NOT NULL DEFAULT 'activo',
+    UNIQUE (evento, participant_code)
+);
+
+INSERT INTO participantes_globales (
+    id, participant_code, evento, nombre, apellido_paterno, apellido_materno, email, sexo, categoria,
+    pago_realizado, ine_path, comprobante_pago_path, fecha_nacimiento, curp, created_at, estado
+)
+SELECT id, participant_code, evento, nombre, apellido_paterno, apellido_materno, email, sexo, categoria,
+    pago_realizado, ine_path, comprobante_pago_path, fecha_nacimiento, curp, created_at, estado
+FROM participantes;
+
+DROP TABLE participantes;
+ALTER TABLE participantes_globales RENAME TO participantes;
+
+CREATE INDEX participantes_evento_categoria ON participantes (evento, categoria);
+CREATE INDEX participantes_evento_sexo ON participantes (evento, sexo);
+CREATE INDEX participantes_evento_creacion ON participantes (evento, created_at);
+CREATE INDEX participantes_evento_estado ON participantes (evento, estado);
+
+-- Los triggers del índice de texto completo se borraron con la tabla
+CREATE TRIGGER participantes_fts_insert AFTER INSERT ON participantes BEGIN
+    INSERT INTO participantes_fts (rowid, nombre, apellido_paterno, apellido_materno, email)
+    VALUES (new.id, new.nombre, new.apellido_paterno, new.apellido_materno, new.email);
+END;
+
+CREATE TRIGGER participantes_fts_delete AFTER DELETE ON participantes BEGIN
+    INSERT INTO participantes_fts (participantes_fts, rowid, nombre, apellido_paterno, apellido_materno, email)
+    VALUES ('delete', old.id, old.nombre, old.apellido_paterno, old.apellido_materno, old.email);
+END;
+
+CREATE TRIGGER participantes_fts_update AFTER UPDATE ON participantes BEGIN
+    INSERT INTO participantes_fts (participantes_fts, rowid, nombre, apellido_paterno, apellido_materno, email)
+    VALUES ('delete', old.id, old.nombre, old.apellido_paterno, old.apellido_materno, old.email);
+    INSERT INTO participantes_fts (rowid, nombre, apellido_paterno, apellido_materno, email)
+    VALUES (new.id, new.nombre, new.apellido_paterno, new.apellido_materno, new.email);
+END;
@@ -2,61 +2,73 @@ package database
 
 import (
 	"database/sql"
-	"fmt"
-	"compilerciclista/src/models"
+	"errors"
+	"strings"
+	"unicode/utf8"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 )
 
-var DB *sql.DB
-
-func InitDB(dataSourceName string) error {
-	var err error
-	DB, err = sql.Open("mysql", dataSourceName)
-	if err != nil {
-		return err
-	}
-
-	return DB.Ping()
+// NewMySQLRepository crea un repositorio sobre una conexión MySQL.
+func NewMySQLRepository(db *sql.DB) *SQLRepository {
+	return &SQLRepository{db: db, q: db, dialect: mysqlDialect{}}
 }
 
-func CountParticipants() (int64, error) {
-	var count int64
-	query := "SELECT COUNT(id) FROM participantes"
-	err := DB.QueryRow(query).Scan(&count)
+type mysqlDialect struct{}
+
+// nextSequence incrementa el contador con una sola sentencia que bloquea su
+// fila, así que dos registros simultáneos nunca obtienen el mismo número;
+// dentro de una transacción el bloqueo dura hasta confirmarla.
+func (mysqlDialect) nextSequence(q Querier, evento, prefijo string) (int64, error) {
+	// LAST_INSERT_ID(expr) hace que el driver devuelva el valor reservado
+	// como LastInsertId, sin otra consulta que pudiera ir a otra conexión.
+	query := `INSERT INTO secuencias_codigo (evento, prefijo, ultimo) VALUES (?, ?, LAST_INSERT_ID(1))
+		ON DUPLICATE KEY UPDATE ultimo = LAST_INSERT_ID(ultimo + 1)`
+	res, err := q.Exec(query, evento, prefijo)
 	if err != nil {
 		return 0, err
 	}
-	return count, nil
+	return res.LastInsertId()
 }
 
-func CreateParticipant(p models.Participant) (int64, error) {
-	// Se añade 'participant_code' al query y a los valores.
-	query := `INSERT INTO participantes (
-		participant_code, nombre, apellido_paterno, apellido_materno, email, sexo, categoria, 
-		pago_realizado, ine_path, comprobante_pago_path
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
-
-	stmt, err := DB.Prepare(query)
-	if err != nil {
-		return 0, fmt.Errorf("error al preparar la consulta: %w", err)
+// search usa el índice FULLTEXT participantes_busqueda en modo booleano,
+// exigiendo cada palabra como prefijo. InnoDB no indexa palabras de menos de
+// tres letras (innodb_ft_min_token_size), así que esas se buscan con LIKE.
+func (mysqlDialect) search(words []string) (string, []interface{}) {
+	var terms []string
+	var conditions []string
+	var args []interface{}
+	for _, word := range words {
+		if utf8.RuneCountInString(word) < 3 {
+			conditions = append(conditions, "CONCAT_WS(' ', nombre, apellido_paterno, apellido_materno, email) LIKE ?")
+			args = append(args, "%"+word+"%")
+		} else {
+			terms = append(terms, "+"+word+"*")
+		}
 	}
-	defer stmt.Close()
-
-	res, err := stmt.Exec(
-		p.ParticipantCode, // <-- Se añade el nuevo valor aquí
-		p.Nombre, p.ApellidoPaterno, p.ApellidoMaterno, p.Email, p.Sexo, p.Categoria,
-		p.PagoRealizado, p.InePath, p.ComprobantePagoPath,
-	)
-	if err != nil {
-		// El error de "Duplicate entry" ahora podría ser por el email o por el participant_code
-		return 0, fmt.Errorf("error al ejecutar la consulta: %w", err)
+	if len(terms) > 0 {
+		conditions = append([]string{"MATCH (nombre, apellido_paterno, apellido_materno, email) AGAINST (? IN BOOLEAN MODE)"}, conditions...)
+		args = append([]interface{}{strings.Join(terms, " ")}, args...)
 	}
+	return "(" + strings.Join(conditions, " AND ") + ")", args
+}
 
-	id, err := res.LastInsertId()
-	if err != nil {
-		return 0, fmt.Errorf("error al obtener el último ID insertado: %w", err)
-	}
+// forUpdate bloquea las filas leídas (InnoDB) hasta confirmar o descartar la
+// transacción: otra transacción que las pida espera en lugar de leer datos
+// que están por cambiar.
+func (mysqlDialect) forUpdate() string {
+	return " FOR UPDATE"
+}
 
-	return id, nil
-}
\ No newline at end of file
+// duplicate traduce el error 1062 ("Duplicate entry ... for key ...").
+func (mysqlDialect) duplicate(err error) *DuplicateError {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) || mysqlErr.Number != 1062 {
+		return nil
+	}
+	key := mysqlErr.Message
+	if i := strings.LastIndex(key, " for key "); i >= 0 {
+		key = key[i:]
+	}
+	return duplicateField(key)
+}
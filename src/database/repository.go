@@ -0,0 +1,81 @@
+package database
+
+import (
+	"compilerciclista/src/models"
+	"errors"
+	"time"
+)
+
+// ParticipantRepository guarda y consulta participantes sin que los handlers
+// dependan de un motor de base de datos concreto.
+type ParticipantRepository interface {
+	// NextSequence reserva el siguiente número de la secuencia de códigos
+	// (evento, prefijo). Los números nunca se reutilizan, aunque pueden
+	// quedar huecos si la transacción que los reservó no se confirma.
+	NextSequence(evento, prefijo string) (int64, error)
+	// Create inserta el participante y devuelve su ID. Si el email, la CURP o
+	// el código ya existen en el mismo evento devuelve un *DuplicateError.
+	Create(p models.Participant) (int64, error)
+	// Update guarda los datos de un participante existente, identificado por
+	// su ID. Como Create, devuelve un *DuplicateError ante un conflicto.
+	Update(p models.Participant) error
+	// RecordAudit agrega una entrada a la auditoría de participantes. Se
+	// llama en la misma transacción que la operación que registra.
+	RecordAudit(entry models.AuditEntry) error
+	// History devuelve la auditoría de un participante, de la más antigua a
+	// la más reciente.
+	History(participantID int64) ([]models.AuditEntry, error)
+	// FindByCode busca un participante del evento por su código; si no
+	// existe devuelve ErrNotFound.
+	FindByCode(evento, code string) (models.Participant, error)
+	// FindByID busca un participante por su ID; si no existe devuelve
+	// ErrNotFound.
+	FindByID(id int64) (models.Participant, error)
+	// FindByCodeForUpdate es FindByCode bloqueando la fila hasta el final de
+	// la transacción, para leer, modificar y guardar un participante sin
+	// perder los cambios de otra transacción simultánea. Se usa dentro de WithTx.
+	FindByCodeForUpdate(evento, code string) (models.Participant, error)
+	// FindByIDForUpdate es FindByID bloqueando la fila, como FindByCodeForUpdate.
+	FindByIDForUpdate(id int64) (models.Participant, error)
+	// List devuelve una página de participantes, en orden de registro, y el
+	// total de los que cumplen el filtro.
+	List(filter ParticipantFilter) ([]models.Participant, int64, error)
+	// WithTx ejecuta fn con un repositorio cuyas operaciones forman una sola
+	// transacción: se confirma si fn devuelve nil y se descarta si no.
+	WithTx(fn func(repo ParticipantRepository) error) error
+}
+
+// ErrDuplicate indica que un participante choca con otro ya registrado.
+var ErrDuplicate = errors.New("participante duplicado")
+
+// DuplicateError indica qué campo único provocó el conflicto ("email",
+// "curp" o "participant_code"; "username" en las cuentas). errors.Is(err, ErrDuplicate) lo reconoce.
+type DuplicateError struct {
+	Field string
+}
+
+func (e *DuplicateError) Error() string {
+	return "ya existe un participante con el mismo " + e.Field
+}
+
+func (e *DuplicateError) Is(target error) bool {
+	return target == ErrDuplicate
+}
+
+// ErrNotFound indica que no existe el participante buscado.
+var ErrNotFound = errors.New("participante no encontrado")
+
+// ParticipantFilter restringe y pagina la lista de participantes de un evento.
+// Los campos vacíos o nil no filtran, salvo Estado: vacío equivale a
+// models.EstadoActivo, para que las inscripciones dadas de baja no aparezcan.
+type ParticipantFilter struct {
+	Evento        string
+	Estado        string
+	Categoria     string
+	Sexo          string
+	PagoRealizado *bool
+	CreatedFrom   *time.Time // inclusive; created_at se guarda en UTC
+	CreatedTo     *time.Time // exclusive
+	Search        string     // palabras buscadas en nombres y email
+	Limit, Offset int
+}
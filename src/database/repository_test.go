@@ -0,0 +1,65 @@
+package database
+
+import (
+	"errors"
+	"testing"
+
+	"compilerciclista/src/models"
+)
+
+func participant(evento, code, email, curp string) models.Participant {
+	return models.Participant{
+		ParticipantCode: code,
+		Evento:          evento,
+		Nombre:          "Ana",
+		ApellidoPaterno: "Pérez",
+		Email:           email,
+		Sexo:            "F",
+		Categoria:       "Elite",
+		CURP:            curp,
+	}
+}
+
+// repositories devuelve las implementaciones que deben aplicar las mismas
+// claves únicas.
+func repositories(t *testing.T) map[string]ParticipantRepository {
+	return map[string]ParticipantRepository{
+		"memoria": NewMemoryRepository(),
+		"sqlite":  newSQLiteTestRepository(t),
+	}
+}
+
+func TestUniqueKeysPerEvent(t *testing.T) {
+	const curp = "PEGA000101MDFRRNA1"
+	for name, repo := range repositories(t) {
+		t.Run(name, func(t *testing.T) {
+			if _, err := repo.Create(participant("Gran Fondo", "ELI-001", "ana@unam.mx", curp)); err != nil {
+				t.Fatalf("primer registro: %v", err)
+			}
+			// La misma persona, con el mismo código, en otro evento
+			if _, err := repo.Create(participant("Clásica", "ELI-001", "ANA@unam.mx", curp)); err != nil {
+				t.Fatalf("registro en otro evento: %v", err)
+			}
+
+			tests := []struct {
+				name  string
+				p     models.Participant
+				field string
+			}{
+				{"email", participant("Gran Fondo", "ELI-002", "Ana@UNAM.mx", ""), "email"},
+				{"curp", participant("Gran Fondo", "ELI-002", "otra@unam.mx", curp), "curp"},
+				{"código", participant("Gran Fondo", "ELI-001", "otra@unam.mx", ""), "participant_code"},
+			}
+			for _, tt := range tests {
+				_, err := repo.Create(tt.p)
+				var duplicate *DuplicateError
+				if !errors.As(err, &duplicate) || duplicate.Field != tt.field {
+					t.Errorf("%s repetido en el evento: err = %v, se esperaba campo %s", tt.name, err, tt.field)
+				}
+				if !errors.Is(err, ErrDuplicate) {
+					t.Errorf("%s repetido: errors.Is(err, ErrDuplicate) = false", tt.name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,438 @@
+package database
+
+import (
+	"compilerciclista/src/models"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var DB *sql.DB
+
+// Motores de base de datos soportados (variable DB_DRIVER).
+const (
+	DriverMySQL  = "mysql"
+	DriverSQLite = "sqlite"
+)
+
+// Querier agrupa los métodos que comparten *sql.DB y *sql.Tx, para que las
+// mismas consultas se puedan ejecutar dentro o fuera de una transacción.
+type Querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Prepare(query string) (*sql.Stmt, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// InitDB abre la conexión global DB con el motor indicado y aplica las
+// migraciones pendientes, de modo que todo despliegue quede con el mismo esquema.
+func InitDB(driver, dataSourceName string) error {
+	var err error
+	DB, err = Open(driver, dataSourceName)
+	if err != nil {
+		return err
+	}
+
+	applied, err := MigrateUp(DB, driver)
+	for _, m := range applied {
+		log.Printf("Migración %04d_%s aplicada.", m.Version, m.Name)
+	}
+	return err
+}
+
+// Open abre y verifica una conexión con el motor indicado, sin migrar.
+func Open(driver, dataSourceName string) (*sql.DB, error) {
+	var db *sql.DB
+	var err error
+	switch driver {
+	case DriverMySQL:
+		db, err = sql.Open("mysql", dataSourceName)
+	case DriverSQLite:
+		db, err = openSQLite(dataSourceName)
+	default:
+		return nil, fmt.Errorf("motor de base de datos desconocido '%s'", driver)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// ConfigFromEnv lee el motor (DB_DRIVER, mysql por defecto) y arma la cadena
+// de conexión: DB_USER, DB_PASSWORD, DB_HOST, DB_PORT y DB_NAME para MySQL,
+// o SQLITE_PATH para SQLite. La sesión de MySQL usa la zona UTC, la misma en
+// la que SQLite guarda CURRENT_TIMESTAMP, para que created_at y los filtros
+// por fecha no dependan de la zona del servidor.
+func ConfigFromEnv() (driver, dataSourceName string) {
+	driver = os.Getenv("DB_DRIVER")
+	if driver == "" {
+		driver = DriverMySQL
+	}
+	if driver == DriverSQLite {
+		dataSourceName = os.Getenv("SQLITE_PATH")
+		if dataSourceName == "" {
+			dataSourceName = "ciclista.db"
+		}
+		return driver, dataSourceName
+	}
+	return driver, fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true&time_zone=%%27%%2B00%%3A00%%27",
+		os.Getenv("DB_USER"),
+		os.Getenv("DB_PASSWORD"),
+		os.Getenv("DB_HOST"),
+		os.Getenv("DB_PORT"),
+		os.Getenv("DB_NAME"),
+	)
+}
+
+// NewRepository crea el repositorio del motor indicado sobre db.
+func NewRepository(driver string, db *sql.DB) (*SQLRepository, error) {
+	switch driver {
+	case DriverMySQL:
+		return NewMySQLRepository(db), nil
+	case DriverSQLite:
+		return NewSQLiteRepository(db), nil
+	default:
+		return nil, fmt.Errorf("motor de base de datos desconocido '%s'", driver)
+	}
+}
+
+// dialect resuelve lo que cambia entre motores SQL.
+type dialect interface {
+	// nextSequence incrementa y devuelve el contador (evento, prefijo).
+	nextSequence(q Querier, evento, prefijo string) (int64, error)
+	// duplicate reconoce las violaciones de claves únicas.
+	duplicate(err error) *DuplicateError
+	// search devuelve la condición que busca las palabras en los nombres y
+	// el email, usando el índice de texto completo del motor.
+	search(words []string) (string, []interface{})
+	// forUpdate es el sufijo que bloquea las filas leídas hasta el final de
+	// la transacción.
+	forUpdate() string
+}
+
+// SQLRepository es el ParticipantRepository respaldado por database/sql.
+type SQLRepository struct {
+	db      *sql.DB
+	q       Querier // db, o la transacción abierta por WithTx
+	dialect dialect
+}
+
+func (r *SQLRepository) NextSequence(evento, prefijo string) (int64, error) {
+	n, err := r.dialect.nextSequence(r.q, evento, prefijo)
+	if err != nil {
+		return 0, fmt.Errorf("error al reservar el número de la secuencia: %w", err)
+	}
+	return n, nil
+}
+
+func (r *SQLRepository) Create(p models.Participant) (int64, error) {
+	query := `INSERT INTO participantes (
+		participant_code, evento, nombre, apellido_paterno, apellido_materno, email, sexo, categoria, 
+		pago_realizado, estado, ine_path, comprobante_pago_path, fecha_nacimiento, curp
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	stmt, err := r.q.Prepare(query)
+	if err != nil {
+		return 0, fmt.Errorf("error al preparar la consulta: %w", err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.Exec(
+		p.ParticipantCode,
+		p.Evento,
+		p.Nombre, p.ApellidoPaterno, p.ApellidoMaterno, p.Email, p.Sexo, p.Categoria,
+		p.PagoRealizado, estadoOrActivo(p.Estado), p.InePath, p.ComprobantePagoPath, nullIfEmpty(p.FechaNacimiento), nullIfEmpty(p.CURP),
+	)
+	if err != nil {
+		if duplicate := r.dialect.duplicate(err); duplicate != nil {
+			return 0, duplicate
+		}
+		return 0, fmt.Errorf("error al ejecutar la consulta: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("error al obtener el último ID insertado: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *SQLRepository) Update(p models.Participant) error {
+	query := `UPDATE participantes SET
+		participant_code = ?, nombre = ?, apellido_paterno = ?, apellido_materno = ?, email = ?, sexo = ?,
+		categoria = ?, pago_realizado = ?, estado = ?, ine_path = ?, comprobante_pago_path = ?, fecha_nacimiento = ?, curp = ?
+	WHERE id = ?`
+	_, err := r.q.Exec(query,
+		p.ParticipantCode, p.Nombre, p.ApellidoPaterno, p.ApellidoMaterno, p.Email, p.Sexo,
+		p.Categoria, p.PagoRealizado, estadoOrActivo(p.Estado), p.InePath, p.ComprobantePagoPath, nullIfEmpty(p.FechaNacimiento), nullIfEmpty(p.CURP),
+		p.ID,
+	)
+	if err != nil {
+		if duplicate := r.dialect.duplicate(err); duplicate != nil {
+			return duplicate
+		}
+		return fmt.Errorf("error al actualizar el participante: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLRepository) RecordAudit(e models.AuditEntry) error {
+	query := `INSERT INTO auditoria_participante (participante_id, accion, actor, antes, despues, request_id)
+	VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := r.q.Exec(query, e.ParticipantID, e.Action, e.Actor,
+		nullIfEmpty(string(e.Before)), nullIfEmpty(string(e.After)), nullIfEmpty(e.RequestID))
+	if err != nil {
+		return fmt.Errorf("error al registrar la auditoría (%s): %w", e.Action, err)
+	}
+	return nil
+}
+
+func (r *SQLRepository) History(participantID int64) ([]models.AuditEntry, error) {
+	query := `SELECT id, participante_id, accion, actor, antes, despues, request_id, created_at
+	FROM auditoria_participante WHERE participante_id = ? ORDER BY id`
+	rows, err := r.q.Query(query, participantID)
+	if err != nil {
+		return nil, fmt.Errorf("error al consultar la auditoría: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.AuditEntry{}
+	for rows.Next() {
+		var e models.AuditEntry
+		var before, after, requestID sql.NullString
+		var created timeValue
+		if err := rows.Scan(&e.ID, &e.ParticipantID, &e.Action, &e.Actor, &before, &after, &requestID, &created); err != nil {
+			return nil, fmt.Errorf("error al leer la auditoría: %w", err)
+		}
+		if before.Valid {
+			e.Before = json.RawMessage(before.String)
+		}
+		if after.Valid {
+			e.After = json.RawMessage(after.String)
+		}
+		e.RequestID = requestID.String
+		if created.Valid {
+			e.CreatedAt = &created.Time
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// participantColumns son las columnas que lee scanParticipant, en su orden.
+const participantColumns = `id, participant_code, evento, nombre, apellido_paterno, apellido_materno, email,
+	sexo, fecha_nacimiento, curp, categoria, pago_realizado, estado, ine_path, comprobante_pago_path, created_at`
+
+// rowScanner es *sql.Row o *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanParticipant(row rowScanner) (models.Participant, error) {
+	var p models.Participant
+	var apellidoMaterno, curp, inePath, comprobantePath sql.NullString
+	var birth, created timeValue
+	err := row.Scan(&p.ID, &p.ParticipantCode, &p.Evento, &p.Nombre, &p.ApellidoPaterno, &apellidoMaterno, &p.Email,
+		&p.Sexo, &birth, &curp, &p.Categoria, &p.PagoRealizado, &p.Estado, &inePath, &comprobantePath, &created)
+	if err != nil {
+		return models.Participant{}, err
+	}
+	p.ApellidoMaterno = apellidoMaterno.String
+	p.CURP = curp.String
+	p.InePath = inePath.String
+	p.ComprobantePagoPath = comprobantePath.String
+	if birth.Valid {
+		p.FechaNacimiento = birth.Time.Format("2006-01-02")
+	}
+	if created.Valid {
+		p.CreatedAt = &created.Time
+	}
+	return p, nil
+}
+
+func (r *SQLRepository) FindByCode(evento, code string) (models.Participant, error) {
+	return r.findOne("evento = ? AND participant_code = ?", "", evento, code)
+}
+
+func (r *SQLRepository) FindByID(id int64) (models.Participant, error) {
+	return r.findOne("id = ?", "", id)
+}
+
+func (r *SQLRepository) FindByCodeForUpdate(evento, code string) (models.Participant, error) {
+	return r.findOne("evento = ? AND participant_code = ?", r.dialect.forUpdate(), evento, code)
+}
+
+func (r *SQLRepository) FindByIDForUpdate(id int64) (models.Participant, error) {
+	return r.findOne("id = ?", r.dialect.forUpdate(), id)
+}
+
+// findOne busca el participante que cumple la condición; lock es el sufijo
+// que bloquea la fila, o vacío.
+func (r *SQLRepository) findOne(condition, lock string, args ...interface{}) (models.Participant, error) {
+	query := "SELECT " + participantColumns + " FROM participantes WHERE " + condition + lock
+	p, err := scanParticipant(r.q.QueryRow(query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Participant{}, ErrNotFound
+	}
+	if err != nil {
+		return models.Participant{}, fmt.Errorf("error al buscar el participante: %w", err)
+	}
+	return p, nil
+}
+
+func (r *SQLRepository) List(filter ParticipantFilter) ([]models.Participant, int64, error) {
+	conditions := []string{"evento = ?", "estado = ?"}
+	args := []interface{}{filter.Evento, estadoOrActivo(filter.Estado)}
+	if filter.Categoria != "" {
+		conditions = append(conditions, "categoria = ?")
+		args = append(args, filter.Categoria)
+	}
+	if filter.Sexo != "" {
+		conditions = append(conditions, "sexo = ?")
+		args = append(args, filter.Sexo)
+	}
+	if filter.PagoRealizado != nil {
+		conditions = append(conditions, "pago_realizado = ?")
+		args = append(args, *filter.PagoRealizado)
+	}
+	if filter.CreatedFrom != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.CreatedFrom.UTC().Format(timestampLayout))
+	}
+	if filter.CreatedTo != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, filter.CreatedTo.UTC().Format(timestampLayout))
+	}
+	if words := SearchWords(filter.Search); len(words) > 0 {
+		condition, searchArgs := r.dialect.search(words)
+		conditions = append(conditions, condition)
+		args = append(args, searchArgs...)
+	}
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	var total int64
+	if err := r.q.QueryRow("SELECT COUNT(id) FROM participantes"+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("error al contar los participantes: %w", err)
+	}
+
+	query := "SELECT " + participantColumns + " FROM participantes" + where + " ORDER BY id LIMIT ? OFFSET ?"
+	rows, err := r.q.Query(query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error al listar los participantes: %w", err)
+	}
+	defer rows.Close()
+
+	participants := []models.Participant{}
+	for rows.Next() {
+		p, err := scanParticipant(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("error al leer un participante: %w", err)
+		}
+		participants = append(participants, p)
+	}
+	return participants, total, rows.Err()
+}
+
+func (r *SQLRepository) WithTx(fn func(repo ParticipantRepository) error) error {
+	if _, inTx := r.q.(*sql.Tx); inTx {
+		return fn(r)
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error al iniciar la transacción: %w", err)
+	}
+	if err := fn(&SQLRepository{db: r.db, q: tx, dialect: r.dialect}); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar la transacción: %w", err)
+	}
+	return nil
+}
+
+// duplicateField deduce el campo único a partir del nombre de la clave o
+// columna que mencione el mensaje de error del motor.
+func duplicateField(key string) *DuplicateError {
+	switch {
+	case strings.Contains(key, "curp"):
+		return &DuplicateError{Field: "curp"}
+	case strings.Contains(key, "email"):
+		return &DuplicateError{Field: "email"}
+	case strings.Contains(key, "username"):
+		return &DuplicateError{Field: "username"}
+	default:
+		return &DuplicateError{Field: "participant_code"}
+	}
+}
+
+// SearchWords separa una búsqueda en palabras, quitando los signos que los
+// motores de texto completo interpretan como operadores.
+func SearchWords(search string) []string {
+	return strings.FieldsFunc(strings.ToLower(search), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// timestampLayout es el formato con el que se comparan las columnas TIMESTAMP,
+// siempre en UTC.
+const timestampLayout = "2006-01-02 15:04:05"
+
+// timeValue lee fechas tanto de drivers que devuelven time.Time (MySQL con
+// parseTime=true) como de los que devuelven texto (SQLite).
+type timeValue struct {
+	Time  time.Time
+	Valid bool
+}
+
+func (t *timeValue) Scan(value interface{}) error {
+	var text string
+	switch v := value.(type) {
+	case nil:
+		t.Valid = false
+		return nil
+	case time.Time:
+		t.Time, t.Valid = v, true
+		return nil
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	default:
+		return fmt.Errorf("no se puede leer %T como fecha", value)
+	}
+	for _, layout := range []string{timestampLayout, "2006-01-02", time.RFC3339Nano} {
+		if parsed, err := time.Parse(layout, text); err == nil {
+			t.Time, t.Valid = parsed, true
+			return nil
+		}
+	}
+	return fmt.Errorf("fecha con formato desconocido '%s'", text)
+}
+
+// estadoOrActivo trata el estado vacío como el inicial.
+func estadoOrActivo(estado string) string {
+	if estado == "" {
+		return models.EstadoActivo
+	}
+	return estado
+}
+
+// nullIfEmpty guarda las cadenas vacías de columnas opcionales como NULL.
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// openSQLite abre (o crea) la base de datos del archivo path.
+func openSQLite(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)")
+	if err != nil {
+		return nil, err
+	}
+	// SQLite admite un solo escritor: con una conexión las transacciones se
+	// esperan entre sí en lugar de fallar con SQLITE_BUSY.
+	db.SetMaxOpenConns(1)
+	return db, nil
+}
+
+// NewSQLiteRepository crea un repositorio sobre una base de datos SQLite.
+func NewSQLiteRepository(db *sql.DB) *SQLRepository {
+	return &SQLRepository{db: db, q: db, dialect: sqliteDialect{}}
+}
+
+type sqliteDialect struct{}
+
+// nextSequence incrementa el contador en una sola sentencia; como SQLite
+// bloquea la base de datos al escribir, no hay dos reservas simultáneas.
+func (sqliteDialect) nextSequence(q Querier, evento, prefijo string) (int64, error) {
+	query := `INSERT INTO secuencias_codigo (evento, prefijo, ultimo) VALUES (?, ?, 1)
+		ON CONFLICT (evento, prefijo) DO UPDATE SET ultimo = ultimo + 1
+		RETURNING ultimo`
+	var n int64
+	err := q.QueryRow(query, evento, prefijo).Scan(&n)
+	return n, err
+}
+
+// forUpdate no agrega nada: SQLite no tiene bloqueos de fila y, con una sola
+// conexión abierta, las transacciones ya se ejecutan una tras otra.
+func (sqliteDialect) forUpdate() string {
+	return ""
+}
+
+// search consulta la tabla FTS5 participantes_fts, que ignora mayúsculas y
+// acentos, exigiendo cada palabra como prefijo.
+func (sqliteDialect) search(words []string) (string, []interface{}) {
+	terms := make([]string, len(words))
+	for i, word := range words {
+		terms[i] = `"` + word + `"*`
+	}
+	return "id IN (SELECT rowid FROM participantes_fts WHERE participantes_fts MATCH ?)", []interface{}{strings.Join(terms, " ")}
+}
+
+// duplicate traduce el error "UNIQUE constraint failed: tabla.columna".
+func (sqliteDialect) duplicate(err error) *DuplicateError {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) || sqliteErr.Code() != sqlite3.SQLITE_CONSTRAINT_UNIQUE {
+		return nil
+	}
+	return duplicateField(sqliteErr.Error())
+}
@@ -0,0 +1,229 @@
+package database
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	"compilerciclista/src/models"
+)
+
+// newSQLiteTestRepository abre un archivo SQLite temporal con todas las
+// migraciones aplicadas.
+func newSQLiteTestRepository(t *testing.T) *SQLRepository {
+	t.Helper()
+	db := openTestDB(t)
+	if _, err := MigrateUp(db, DriverSQLite); err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+	return NewSQLiteRepository(db)
+}
+
+func TestSQLiteMigrateFreshFile(t *testing.T) {
+	db := openTestDB(t)
+	applied, err := MigrateUp(db, DriverSQLite)
+	if err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+	migrations, err := Migrations(DriverSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != len(migrations) {
+		t.Errorf("se aplicaron %d de %d migraciones", len(applied), len(migrations))
+	}
+	again, err := MigrateUp(db, DriverSQLite)
+	if err != nil || len(again) != 0 {
+		t.Errorf("segundo MigrateUp = %d migraciones, %v; no debía aplicar nada", len(again), err)
+	}
+	// Las tablas que usan los repositorios existen y están vacías
+	for _, table := range []string{"participantes", "secuencias_codigo", "auditoria_participante", "usuarios", "participantes_fts"} {
+		var n int
+		if err := db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&n); err != nil || n != 0 {
+			t.Errorf("%s: %d filas, %v", table, n, err)
+		}
+	}
+}
+
+func TestSQLiteDuplicateError(t *testing.T) {
+	repo := newSQLiteTestRepository(t)
+	id, err := repo.Create(participant("Gran Fondo", "ELI-001", "ana@unam.mx", "PEGA000101MDFRRNA1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherID, err := repo.Create(participant("Gran Fondo", "ELI-002", "eva@unam.mx", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.CreateUser(models.User{Username: "mesa1", PasswordHash: "x", Rol: models.RolMesa}); err != nil {
+		t.Fatal(err)
+	}
+
+	other := participant("Gran Fondo", "ELI-002", "eva@unam.mx", "")
+	other.ID = otherID
+	tests := []struct {
+		name  string
+		run   func() error
+		field string
+	}{
+		{"email sin distinguir mayúsculas", func() error {
+			_, err := repo.Create(participant("Gran Fondo", "ELI-003", "ANA@UNAM.MX", ""))
+			return err
+		}, "email"},
+		{"curp", func() error {
+			_, err := repo.Create(participant("Gran Fondo", "ELI-003", "luz@unam.mx", "PEGA000101MDFRRNA1"))
+			return err
+		}, "curp"},
+		{"código", func() error {
+			_, err := repo.Create(participant("Gran Fondo", "ELI-001", "luz@unam.mx", ""))
+			return err
+		}, "participant_code"},
+		{"update al email de otro", func() error {
+			changed := other
+			changed.Email = "ana@unam.mx"
+			return repo.Update(changed)
+		}, "email"},
+		{"usuario", func() error {
+			_, err := repo.CreateUser(models.User{Username: "mesa1", PasswordHash: "y", Rol: models.RolJuez})
+			return err
+		}, "username"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			var duplicate *DuplicateError
+			if !errors.As(err, &duplicate) {
+				t.Fatalf("err = %v, se esperaba *DuplicateError", err)
+			}
+			if duplicate.Field != tt.field {
+				t.Errorf("Field = %q, se esperaba %q", duplicate.Field, tt.field)
+			}
+		})
+	}
+
+	// Otras restricciones no se confunden con claves duplicadas
+	if err := repo.RecordAudit(models.AuditEntry{ParticipantID: id + otherID + 100, Action: "alta", Actor: "mesa"}); err == nil || errors.Is(err, ErrDuplicate) {
+		t.Errorf("clave foránea inválida: err = %v", err)
+	}
+}
+
+func TestSQLiteNextSequenceConcurrent(t *testing.T) {
+	repo := newSQLiteTestRepository(t)
+	const workers = 20
+
+	numbers := make([]int64, workers)
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = repo.WithTx(func(tx ParticipantRepository) error {
+				n, err := tx.NextSequence("Gran Fondo", "ELI")
+				numbers[i] = n
+				return err
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("reserva %d: %v", i, err)
+		}
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+	for i, n := range numbers {
+		if n != int64(i+1) {
+			t.Fatalf("números reservados = %v, se esperaba 1..%d sin repetir", numbers, workers)
+		}
+	}
+
+	// Cada (evento, prefijo) lleva su propia cuenta
+	if n, err := repo.NextSequence("Clásica", "ELI"); err != nil || n != 1 {
+		t.Errorf("otro evento: NextSequence = %d, %v; se esperaba 1", n, err)
+	}
+}
+
+func TestSQLiteSearch(t *testing.T) {
+	repo := newSQLiteTestRepository(t)
+	people := []models.Participant{
+		participant("Gran Fondo", "ELI-001", "jose@unam.mx", ""),
+		participant("Gran Fondo", "ELI-002", "maria@ipn.mx", ""),
+		participant("Clásica", "ELI-001", "otro@unam.mx", ""),
+	}
+	people[0].Nombre, people[0].ApellidoPaterno = "José", "Núñez"
+	people[1].Nombre, people[1].ApellidoPaterno = "María", "Pérez"
+	people[2].Nombre, people[2].ApellidoPaterno = "José", "Núñez"
+	ids := make([]int64, len(people))
+	for i, p := range people {
+		id, err := repo.Create(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = id
+	}
+
+	search := func(text string) []string {
+		t.Helper()
+		list, total, err := repo.List(ParticipantFilter{Evento: "Gran Fondo", Search: text, Limit: 10})
+		if err != nil {
+			t.Fatalf("List(%q): %v", text, err)
+		}
+		if total != int64(len(list)) {
+			t.Errorf("List(%q): total = %d con %d resultados", text, total, len(list))
+		}
+		codes := make([]string, len(list))
+		for i, p := range list {
+			codes[i] = p.ParticipantCode
+		}
+		sort.Strings(codes)
+		return codes
+	}
+
+	tests := []struct {
+		search string
+		want   []string
+	}{
+		{"jose", []string{"ELI-001"}},        // sin acento
+		{"NUÑEZ", []string{"ELI-001"}},       // mayúsculas
+		{"per", []string{"ELI-002"}},         // prefijo
+		{"maria perez", []string{"ELI-002"}}, // todas las palabras
+		{"maria nunez", []string{}},
+		{"unam", []string{"ELI-001"}}, // email, solo del evento filtrado
+		{"ipn.mx", []string{"ELI-002"}},
+		{`"Pér*"`, []string{"ELI-002"}}, // sin operadores de FTS5
+	}
+	for _, tt := range tests {
+		if got := search(tt.search); !equalStrings(got, tt.want) {
+			t.Errorf("búsqueda %q = %v, se esperaba %v", tt.search, got, tt.want)
+		}
+	}
+
+	// Los triggers mantienen el índice al corregir un nombre
+	renamed := people[1]
+	renamed.ID = ids[1]
+	renamed.ApellidoPaterno = "Gómez"
+	if err := repo.Update(renamed); err != nil {
+		t.Fatal(err)
+	}
+	if got := search("perez"); len(got) != 0 {
+		t.Errorf("tras corregir el apellido, 'perez' = %v", got)
+	}
+	if got := search("gomez"); !equalStrings(got, []string{"ELI-002"}) {
+		t.Errorf("tras corregir el apellido, 'gomez' = %v", got)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,104 @@
+package database
+
+import (
+	"compilerciclista/src/models"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// UserRepository guarda las cuentas con las que se entra a la API.
+type UserRepository interface {
+	// CountUsers devuelve el número de cuentas.
+	CountUsers() (int64, error)
+	// CreateUser inserta la cuenta y devuelve su ID. Si el nombre de usuario
+	// ya existe devuelve un *DuplicateError con Field "username".
+	CreateUser(u models.User) (int64, error)
+	// FindUser busca una cuenta por su nombre de usuario; si no existe
+	// devuelve ErrUserNotFound.
+	FindUser(username string) (models.User, error)
+}
+
+// ErrUserNotFound indica que no existe la cuenta buscada.
+var ErrUserNotFound = errors.New("usuario no encontrado")
+
+func (r *SQLRepository) CountUsers() (int64, error) {
+	var count int64
+	if err := r.q.QueryRow("SELECT COUNT(id) FROM usuarios").Scan(&count); err != nil {
+		return 0, fmt.Errorf("error al contar los usuarios: %w", err)
+	}
+	return count, nil
+}
+
+func (r *SQLRepository) CreateUser(u models.User) (int64, error) {
+	var participantID interface{}
+	if u.ParticipantID != 0 {
+		participantID = u.ParticipantID
+	}
+	res, err := r.q.Exec("INSERT INTO usuarios (username, password_hash, rol, participante_id) VALUES (?, ?, ?, ?)",
+		u.Username, u.PasswordHash, u.Rol, participantID)
+	if err != nil {
+		if duplicate := r.dialect.duplicate(err); duplicate != nil {
+			return 0, duplicate
+		}
+		return 0, fmt.Errorf("error al crear el usuario: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("error al obtener el último ID insertado: %w", err)
+	}
+	return id, nil
+}
+
+func (r *SQLRepository) FindUser(username string) (models.User, error) {
+	var u models.User
+	var participantID sql.NullInt64
+	var created timeValue
+	err := r.q.QueryRow("SELECT id, username, password_hash, rol, participante_id, created_at FROM usuarios WHERE username = ?", username).
+		Scan(&u.ID, &u.Username, &u.PasswordHash, &u.Rol, &participantID, &created)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.User{}, ErrUserNotFound
+	}
+	if err != nil {
+		return models.User{}, fmt.Errorf("error al buscar el usuario: %w", err)
+	}
+	u.ParticipantID = participantID.Int64
+	if created.Valid {
+		u.CreatedAt = &created.Time
+	}
+	return u, nil
+}
+
+func (r *MemoryRepository) CountUsers() (int64, error) {
+	return int64(len(r.snapshot().users)), nil
+}
+
+func (r *MemoryRepository) CreateUser(u models.User) (int64, error) {
+	var id int64
+	err := r.WithTx(func(repo ParticipantRepository) error {
+		state := repo.(*memoryTx).state
+		for _, existing := range state.users {
+			if strings.EqualFold(existing.Username, u.Username) {
+				return &DuplicateError{Field: "username"}
+			}
+		}
+		now := time.Now()
+		u.ID = int64(len(state.users)) + 1
+		u.CreatedAt = &now
+		state.users = append(state.users, u)
+		id = u.ID
+		return nil
+	})
+	return id, err
+}
+
+func (r *MemoryRepository) FindUser(username string) (models.User, error) {
+	for _, u := range r.snapshot().users {
+		if strings.EqualFold(u.Username, username) {
+			return u, nil
+		}
+	}
+	return models.User{}, ErrUserNotFound
+}
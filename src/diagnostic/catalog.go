@@ -0,0 +1,137 @@
+package diagnostic
+
+// Code identifica de forma estable cada tipo de diagnóstico, para que los
+// clientes puedan reaccionar a él sin depender del texto del mensaje.
+// El prefijo indica la etapa: LEX (léxico), PAR (sintaxis), SEM (semántica)
+// y SCH (esquema del evento).
+type Code string
+
+const (
+	LexUnexpectedChar      Code = "LEX001"
+	LexUnterminatedString  Code = "LEX002"
+	LexInvalidEscape       Code = "LEX003"
+	LexMalformedDate       Code = "LEX004"
+	LexUnterminatedComment Code = "LEX005"
+
+	ParExpectedIdent   Code = "PAR001"
+	ParExpectedToken   Code = "PAR002"
+	ParInvalidValue    Code = "PAR003"
+	ParIntOutOfRange   Code = "PAR004"
+	ParInvalidBool     Code = "PAR005"
+	ParInvalidFloat    Code = "PAR006"
+	ParInvalidDate     Code = "PAR007"
+	ParDuplicateKey    Code = "PAR008"
+	ParExpectedBlock   Code = "PAR009"
+	ParUnclosedBlock   Code = "PAR010"
+	ParTooManyErrors   Code = "PAR011"
+	ParUnexpectedBlock Code = "PAR012"
+
+	SemUnknownField     Code = "SEM001"
+	SemUnknownFieldHint Code = "SEM002"
+	SemMissingField     Code = "SEM003"
+	SemMissingFieldHint Code = "SEM004"
+	SemWrongType        Code = "SEM005"
+	SemValueNotAllowed  Code = "SEM006"
+	SemPatternMismatch  Code = "SEM007"
+	SemBelowMinimum     Code = "SEM008"
+	SemAboveMaximum     Code = "SEM009"
+	SemRuleViolated     Code = "SEM010"
+	SemInvalidEmail     Code = "SEM011"
+	SemEmailNotAllowed  Code = "SEM012"
+	SemEmailDenied      Code = "SEM013"
+	SemEmailDisposable  Code = "SEM014"
+	SemEmailNoMX        Code = "SEM015"
+	SemCategoryAssigned Code = "SEM016"
+	SemCategoryMismatch Code = "SEM017"
+	SemNoCategory       Code = "SEM018"
+	SemBornAfterRace    Code = "SEM019"
+	SemInvalidCURP      Code = "SEM020"
+	SemCURPCheckDigit   Code = "SEM021"
+	SemCURPBirthDate    Code = "SEM022"
+	SemCURPSexo         Code = "SEM023"
+	SemCategoryNoBirth  Code = "SEM024"
+
+	SchUnknownBlock    Code = "SCH001"
+	SchMissingKey      Code = "SCH002"
+	SchWrongKeyType    Code = "SCH003"
+	SchUnknownKey      Code = "SCH004"
+	SchUnknownType     Code = "SCH005"
+	SchInapplicable    Code = "SCH006"
+	SchDuplicateField  Code = "SCH007"
+	SchUndeclaredField Code = "SCH008"
+	SchInvalidPattern  Code = "SCH009"
+	SchUnknownFormat   Code = "SCH010"
+	SchNeedsEmail      Code = "SCH011"
+	SchInvalidAgeRange Code = "SCH012"
+	SchModelField      Code = "SCH013"
+	SchModelCategory   Code = "SCH014"
+)
+
+// entry es la severidad y la plantilla del mensaje de un código.
+type entry struct {
+	severity Severity
+	message  string
+}
+
+// catalog contiene las plantillas en español; los argumentos de cada
+// diagnóstico se aplican con fmt en el orden en que aparecen.
+var catalog = map[Code]entry{
+	LexUnexpectedChar:      {Error, "Error léxico: caracter inesperado '%s'"},
+	LexUnterminatedString:  {Error, "Error léxico: cadena sin terminar, falta la comilla de cierre '\"'"},
+	LexInvalidEscape:       {Error, "Error léxico: secuencia de escape no válida en la cadena"},
+	LexMalformedDate:       {Error, "Error léxico: fecha mal formada '%s', use el formato AAAA-MM-DD"},
+	LexUnterminatedComment: {Error, "Error léxico: comentario de bloque sin terminar, falta '*/'"},
+
+	ParExpectedIdent:   {Error, "Error de sintaxis: se esperaba un identificador, se obtuvo %s"},
+	ParExpectedToken:   {Error, "Error de sintaxis: se esperaba el token %s, se obtuvo %s"},
+	ParInvalidValue:    {Error, "Error de sintaxis: se encontró un tipo de valor no válido %s"},
+	ParIntOutOfRange:   {Error, "Error de sintaxis: el número '%s' está fuera de rango"},
+	ParInvalidBool:     {Error, "Error de sintaxis: no se pudo convertir '%s' a booleano"},
+	ParInvalidFloat:    {Error, "Error de sintaxis: no se pudo convertir '%s' a número decimal"},
+	ParInvalidDate:     {Error, "Error de sintaxis: la fecha '%s' no existe en el calendario"},
+	ParDuplicateKey:    {Error, "Error de sintaxis: la clave '%s' está duplicada, ya se definió en la línea %d"},
+	ParExpectedBlock:   {Error, "Error de sintaxis: se esperaba un bloque 'nombre { ... }', se obtuvo %s"},
+	ParUnclosedBlock:   {Error, "Error de sintaxis: falta '}' para cerrar el bloque '%s'"},
+	ParTooManyErrors:   {Error, "Se alcanzó el límite de %d errores; el resto del documento no fue analizado."},
+	ParUnexpectedBlock: {Error, "Error de sintaxis: se esperaba un bloque '%s { ... }', se obtuvo %s"},
+
+	SemUnknownField:     {Warning, "Advertencia: el campo '%s' no es reconocido y será ignorado."},
+	SemUnknownFieldHint: {Warning, "Advertencia: el campo '%s' no es reconocido y será ignorado. ¿Quiso decir '%s'?"},
+	SemMissingField:     {Error, "Error semántico: el campo requerido '%s' no fue encontrado."},
+	SemMissingFieldHint: {Error, "Error semántico: el campo requerido '%s' no fue encontrado (¿lo escribió como '%s'?)."},
+	SemWrongType:        {Error, "Error semántico: el campo '%s' debe ser %s, se obtuvo %s."},
+	SemValueNotAllowed:  {Error, "Error semántico: el valor '%s' no es válido para '%s'; se esperaba uno de: %s."},
+	SemPatternMismatch:  {Error, "Error semántico: el valor de '%s' no tiene el formato esperado."},
+	SemBelowMinimum:     {Error, "Error semántico: el valor de '%s' debe ser al menos %v."},
+	SemAboveMaximum:     {Error, "Error semántico: el valor de '%s' debe ser como máximo %v."},
+	SemRuleViolated:     {Error, "Error semántico: si '%s' es %v, '%s' no puede estar vacío."},
+	SemInvalidEmail:     {Error, "Error semántico: '%s' no es una dirección de correo electrónico válida."},
+	SemEmailNotAllowed:  {Error, "Error semántico: no se aceptan correos de '%s'; use uno de: %s."},
+	SemEmailDenied:      {Error, "Error semántico: no se aceptan correos de '%s'."},
+	SemEmailDisposable:  {Error, "Error semántico: '%s' es un servicio de correo desechable; use una dirección permanente."},
+	SemEmailNoMX:        {Error, "Error semántico: el dominio '%s' no puede recibir correo."},
+	SemCategoryAssigned: {Info, "Información: se asignó la categoría '%s' por la edad de %d años el día de la carrera."},
+	SemCategoryMismatch: {Error, "Error semántico: la categoría '%s' no corresponde a la edad de %d años el día de la carrera; le corresponde '%s'."},
+	SemNoCategory:       {Error, "Error semántico: no hay una categoría para la edad de %d años y sexo '%s'."},
+	SemBornAfterRace:    {Error, "Error semántico: la fecha de nacimiento es posterior al día de la carrera."},
+	SemInvalidCURP:      {Error, "Error semántico: '%s' no tiene la estructura de una CURP."},
+	SemCURPCheckDigit:   {Error, "Error semántico: el dígito verificador de la CURP '%s' no es correcto."},
+	SemCURPBirthDate:    {Error, "Error semántico: la CURP indica la fecha de nacimiento %s, pero '%s' es %s."},
+	SemCURPSexo:         {Error, "Error semántico: la CURP indica el sexo '%s', pero '%s' es '%s'."},
+	SemCategoryNoBirth:  {Error, "Error semántico: la categoría '%s' depende de la edad; indique 'fecha_nacimiento' para verificarla."},
+
+	SchUnknownBlock:    {Error, "Error de esquema: bloque desconocido '%s', se esperaba 'evento', 'campo', 'regla' o 'categoria'"},
+	SchMissingKey:      {Error, "Error de esquema: falta la clave '%s' en el bloque '%s'"},
+	SchWrongKeyType:    {Error, "Error de esquema: '%s' debe ser %s"},
+	SchUnknownKey:      {Error, "Error de esquema: clave desconocida '%s' en el bloque '%s'"},
+	SchUnknownType:     {Error, "Error de esquema: tipo desconocido '%s'; use cadena, booleano, entero, decimal, fecha o lista"},
+	SchInapplicable:    {Error, "Error de esquema: '%s' no aplica a campos de tipo %s"},
+	SchDuplicateField:  {Error, "Error de esquema: el campo '%s' ya fue declarado"},
+	SchUndeclaredField: {Error, "Error de esquema: la regla menciona el campo '%s', que no está declarado"},
+	SchInvalidPattern:  {Error, "Error de esquema: el patrón no es una expresión regular válida: %v"},
+	SchUnknownFormat:   {Error, "Error de esquema: formato desconocido '%s'; use 'email' o 'curp'"},
+	SchNeedsEmail:      {Error, "Error de esquema: '%s' solo aplica a campos con formato 'email'"},
+	SchInvalidAgeRange: {Error, "Error de esquema: la edad mínima %d es mayor que la máxima %d"},
+	SchModelField:      {Error, "Error de esquema: el registro siempre guarda '%s'; declárelo con tipo 'cadena' y 'requerido: true'"},
+	SchModelCategory:   {Error, "Error de esquema: el registro siempre guarda 'categoria'; declárela de tipo 'cadena' y requerida, o asígnela por edad con 'asignar_categoria' y bloques 'categoria'"},
+}
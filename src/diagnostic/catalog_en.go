@@ -0,0 +1,69 @@
+package diagnostic
+
+// english traduce las plantillas del catálogo. Cada plantilla recibe los
+// mismos argumentos, en el mismo orden, que su versión en español.
+var english = map[Code]string{
+	LexUnexpectedChar:      "Lexical error: unexpected character '%s'",
+	LexUnterminatedString:  "Lexical error: unterminated string, missing closing quote '\"'",
+	LexInvalidEscape:       "Lexical error: invalid escape sequence in string",
+	LexMalformedDate:       "Lexical error: malformed date '%s', use the YYYY-MM-DD format",
+	LexUnterminatedComment: "Lexical error: unterminated block comment, missing '*/'",
+
+	ParExpectedIdent:   "Syntax error: expected an identifier, got %s",
+	ParExpectedToken:   "Syntax error: expected token %s, got %s",
+	ParInvalidValue:    "Syntax error: invalid value type %s",
+	ParIntOutOfRange:   "Syntax error: the number '%s' is out of range",
+	ParInvalidBool:     "Syntax error: could not convert '%s' to a boolean",
+	ParInvalidFloat:    "Syntax error: could not convert '%s' to a decimal number",
+	ParInvalidDate:     "Syntax error: the date '%s' does not exist in the calendar",
+	ParDuplicateKey:    "Syntax error: the key '%s' is duplicated, it was already defined on line %d",
+	ParExpectedBlock:   "Syntax error: expected a 'name { ... }' block, got %s",
+	ParUnclosedBlock:   "Syntax error: missing '}' to close the '%s' block",
+	ParTooManyErrors:   "Reached the limit of %d errors; the rest of the document was not analyzed.",
+	ParUnexpectedBlock: "Syntax error: expected a '%s { ... }' block, got %s",
+
+	SemUnknownField:     "Warning: the field '%s' is not recognized and will be ignored.",
+	SemUnknownFieldHint: "Warning: the field '%s' is not recognized and will be ignored. Did you mean '%s'?",
+	SemMissingField:     "Semantic error: the required field '%s' was not found.",
+	SemMissingFieldHint: "Semantic error: the required field '%s' was not found (did you write it as '%s'?).",
+	SemWrongType:        "Semantic error: the field '%s' must be %s, got %s.",
+	SemValueNotAllowed:  "Semantic error: the value '%s' is not valid for '%s'; expected one of: %s.",
+	SemPatternMismatch:  "Semantic error: the value of '%s' does not have the expected format.",
+	SemBelowMinimum:     "Semantic error: the value of '%s' must be at least %v.",
+	SemAboveMaximum:     "Semantic error: the value of '%s' must be at most %v.",
+	SemRuleViolated:     "Semantic error: if '%s' is %v, '%s' cannot be empty.",
+	SemInvalidEmail:     "Semantic error: '%s' is not a valid email address.",
+	SemEmailNotAllowed:  "Semantic error: email addresses from '%s' are not accepted; use one of: %s.",
+	SemEmailDenied:      "Semantic error: email addresses from '%s' are not accepted.",
+	SemEmailDisposable:  "Semantic error: '%s' is a disposable email service; use a permanent address.",
+	SemEmailNoMX:        "Semantic error: the domain '%s' cannot receive email.",
+	SemCategoryAssigned: "Info: the category '%s' was assigned for the age of %d years on race day.",
+	SemCategoryMismatch: "Semantic error: the category '%s' does not match the age of %d years on race day; the right one is '%s'.",
+	SemNoCategory:       "Semantic error: there is no category for the age of %d years and sexo '%s'.",
+	SemBornAfterRace:    "Semantic error: the birth date is after race day.",
+	SemInvalidCURP:      "Semantic error: '%s' does not have the structure of a CURP.",
+	SemCURPCheckDigit:   "Semantic error: the check digit of the CURP '%s' is not correct.",
+	SemCURPBirthDate:    "Semantic error: the CURP gives the birth date %s, but '%s' is %s.",
+	SemCURPSexo:         "Semantic error: the CURP gives the sexo '%s', but '%s' is '%s'.",
+	SemCategoryNoBirth:  "Semantic error: the category '%s' depends on age; give 'fecha_nacimiento' to verify it.",
+
+	SchUnknownBlock:    "Schema error: unknown block '%s', expected 'evento', 'campo', 'regla' or 'categoria'",
+	SchMissingKey:      "Schema error: missing key '%s' in the '%s' block",
+	SchWrongKeyType:    "Schema error: '%s' must be %s",
+	SchUnknownKey:      "Schema error: unknown key '%s' in the '%s' block",
+	SchUnknownType:     "Schema error: unknown type '%s'; use cadena, booleano, entero, decimal, fecha or lista",
+	SchInapplicable:    "Schema error: '%s' does not apply to fields of type %s",
+	SchDuplicateField:  "Schema error: the field '%s' was already declared",
+	SchUndeclaredField: "Schema error: the rule mentions the field '%s', which is not declared",
+	SchInvalidPattern:  "Schema error: the pattern is not a valid regular expression: %v",
+	SchUnknownFormat:   "Schema error: unknown format '%s'; use 'email' or 'curp'",
+	SchNeedsEmail:      "Schema error: '%s' only applies to fields with the 'email' format",
+	SchInvalidAgeRange: "Schema error: the minimum age %d is greater than the maximum %d",
+	SchModelField:      "Schema error: every registration stores '%s'; declare it with type 'cadena' and 'requerido: true'",
+	SchModelCategory:   "Schema error: every registration stores 'categoria'; declare it with type 'cadena' and required, or assign it by age with 'asignar_categoria' and 'categoria' blocks",
+}
+
+// translations agrupa los catálogos de los idiomas distintos del base.
+var translations = map[Lang]map[Code]string{
+	English: english,
+}
@@ -0,0 +1,116 @@
+package diagnostic
+
+import (
+	"compilerciclista/src/token"
+	"fmt"
+)
+
+// Severity indica si un diagnóstico impide el registro o es solo informativo.
+type Severity string
+
+const (
+	Error   Severity = "error"
+	Warning Severity = "warning"
+	Info    Severity = "info"
+)
+
+// Diagnostic describe un problema encontrado en un documento DSL junto con
+// el rango exacto del texto que lo provocó. Field es la clave del documento
+// afectada, si la hay, para que los clientes marquen el campo del formulario.
+type Diagnostic struct {
+	Code     Code       `json:"code"`
+	Severity Severity   `json:"severity"`
+	Field    string     `json:"field,omitempty"`
+	Span     token.Span `json:"span"`
+	Message  string     `json:"message"`
+
+	args   []interface{}
+	custom bool // el mensaje no sale del catálogo y no se traduce
+}
+
+// New crea el diagnóstico de un código del catálogo para el rango indicado.
+// La severidad y el mensaje salen del catálogo; args completa la plantilla.
+func New(code Code, span token.Span, args ...interface{}) Diagnostic {
+	return Diagnostic{
+		Code:     code,
+		Severity: lookup(code).severity,
+		Span:     span,
+		Message:  render(code, DefaultLang, args),
+		args:     args,
+	}
+}
+
+// Custom crea un diagnóstico con un mensaje propio en lugar de la plantilla
+// del catálogo, por ejemplo el que un organizador escribe en el esquema.
+func Custom(code Code, span token.Span, message string) Diagnostic {
+	return Diagnostic{Code: code, Severity: lookup(code).severity, Span: span, Message: message, custom: true}
+}
+
+// Localize devuelve una copia del diagnóstico con el mensaje en el idioma
+// indicado. Los mensajes propios (ver Custom) se conservan tal cual.
+func (d Diagnostic) Localize(lang Lang) Diagnostic {
+	if !d.custom {
+		d.Message = render(d.Code, lang, d.args)
+	}
+	return d
+}
+
+// LocalizeAll traduce una lista de diagnósticos.
+func LocalizeAll(diagnostics []Diagnostic, lang Lang) []Diagnostic {
+	localized := make([]Diagnostic, len(diagnostics))
+	for i, d := range diagnostics {
+		localized[i] = d.Localize(lang)
+	}
+	return localized
+}
+
+// render aplica los argumentos a la plantilla del código en el idioma
+// indicado, traduciendo también los argumentos que son Term.
+func render(code Code, lang Lang, args []interface{}) string {
+	template := lookup(code).message
+	if translated, ok := translations[lang][code]; ok {
+		template = translated
+	}
+
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		if term, ok := arg.(Term); ok {
+			values[i] = term.Translate(lang)
+		} else {
+			values[i] = arg
+		}
+	}
+	return fmt.Sprintf(template, values...)
+}
+
+func lookup(code Code) entry {
+	e, ok := catalog[code]
+	if !ok {
+		panic(fmt.Sprintf("diagnostic: código %s sin entrada en el catálogo", code))
+	}
+	return e
+}
+
+// WithField devuelve una copia del diagnóstico asociada a la clave field.
+func (d Diagnostic) WithField(field string) Diagnostic {
+	d.Field = field
+	return d
+}
+
+// HasErrors indica si la lista contiene al menos un diagnóstico de error.
+func HasErrors(diagnostics []Diagnostic) bool {
+	for _, d := range diagnostics {
+		if d.Severity == Error {
+			return true
+		}
+	}
+	return false
+}
+
+// String antepone la línea y columna al mensaje cuando se conoce la posición.
+func (d Diagnostic) String() string {
+	if !d.Span.Start.IsValid() {
+		return d.Message
+	}
+	return fmt.Sprintf("línea %d, columna %d: %s", d.Span.Start.Line, d.Span.Start.Column, d.Message)
+}
@@ -0,0 +1,91 @@
+package diagnostic
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Lang es un idioma en el que se pueden mostrar los mensajes.
+type Lang string
+
+const (
+	Spanish Lang = "es"
+	English Lang = "en"
+)
+
+// DefaultLang es el idioma del catálogo base.
+const DefaultLang = Spanish
+
+// Term es un argumento de un mensaje que también debe traducirse, como el
+// nombre de un tipo de dato.
+type Term interface {
+	Translate(lang Lang) string
+}
+
+// Phrase es un Term con su texto en cada idioma soportado.
+type Phrase struct {
+	ES string
+	EN string
+}
+
+func (p Phrase) Translate(lang Lang) string {
+	if lang == English && p.EN != "" {
+		return p.EN
+	}
+	return p.ES
+}
+
+// String permite usar la frase directamente con fmt; devuelve el español.
+func (p Phrase) String() string {
+	return p.ES
+}
+
+// ParseLang interpreta un código de idioma como "en", "en-US" o "es_MX".
+func ParseLang(s string) (Lang, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if i := strings.IndexAny(s, "-_"); i >= 0 {
+		s = s[:i]
+	}
+	switch Lang(s) {
+	case Spanish, English:
+		return Lang(s), true
+	default:
+		return "", false
+	}
+}
+
+// NegotiateLang elige el idioma a partir de una cabecera Accept-Language,
+// respetando los pesos q. Si ningún idioma es soportado devuelve DefaultLang.
+func NegotiateLang(header string) Lang {
+	type candidate struct {
+		lang Lang
+		q    float64
+	}
+	var candidates []candidate
+
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		lang, ok := ParseLang(fields[0])
+		if !ok {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			if value, found := strings.CutPrefix(strings.TrimSpace(param), "q="); found {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{lang, q})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return DefaultLang
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang
+}
@@ -0,0 +1,105 @@
+package diagnostic
+
+import (
+	"compilerciclista/src/token"
+	"strings"
+	"testing"
+)
+
+func TestParseLang(t *testing.T) {
+	tests := []struct {
+		input string
+		lang  Lang
+		ok    bool
+	}{
+		{"es", Spanish, true},
+		{"en", English, true},
+		{"en-US", English, true},
+		{"EN_gb", English, true},
+		{" es-MX ", Spanish, true},
+		{"fr", "", false},
+		{"english", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		lang, ok := ParseLang(tt.input)
+		if lang != tt.lang || ok != tt.ok {
+			t.Errorf("ParseLang(%q) = %q, %v; se esperaba %q, %v", tt.input, lang, ok, tt.lang, tt.ok)
+		}
+	}
+}
+
+func TestNegotiateLang(t *testing.T) {
+	tests := []struct {
+		header string
+		want   Lang
+	}{
+		{"", Spanish},
+		{"en", English},
+		{"en-US", English},
+		{"en-US,en;q=0.9", English},
+		{"es-MX,es;q=0.9,en;q=0.8", Spanish},
+		{"es;q=0.5, en;q=0.8", English},
+		{"en;q=0.8, es;q=0.8", English}, // con el mismo peso gana el primero
+		{"fr-FR,fr;q=0.9,en;q=0.5", English},
+		{"fr, de;q=0.9", Spanish}, // ninguno soportado
+		{"*", Spanish},
+		{"en;q=0", Spanish}, // q=0 significa "no aceptable"
+		{"en;q=0, es;q=0", Spanish},
+		{"en;q=abc", English}, // un peso ilegible cuenta como 1
+		{"zh;q=1, en ; q=0.2", English},
+	}
+	for _, tt := range tests {
+		if got := NegotiateLang(tt.header); got != tt.want {
+			t.Errorf("NegotiateLang(%q) = %q, se esperaba %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	span := token.Span{Start: token.Position{Line: 2, Column: 5}}
+	d := New(SemWrongType, span, "edad", Phrase{ES: "un número entero", EN: "an integer"}, Phrase{ES: "un texto", EN: "a string"})
+
+	if want := "Error semántico: el campo 'edad' debe ser un número entero, se obtuvo un texto."; d.Message != want {
+		t.Errorf("mensaje en español = %q, se esperaba %q", d.Message, want)
+	}
+	english := d.Localize(English)
+	if want := "Semantic error: the field 'edad' must be an integer, got a string."; english.Message != want {
+		t.Errorf("mensaje en inglés = %q, se esperaba %q", english.Message, want)
+	}
+	if english.Code != d.Code || english.Span != d.Span || english.Severity != d.Severity {
+		t.Errorf("Localize cambió algo más que el mensaje: %+v", english)
+	}
+	if back := english.Localize(Spanish); back.Message != d.Message {
+		t.Errorf("volver al español = %q", back.Message)
+	}
+	if other := d.Localize("fr"); other.Message != d.Message {
+		t.Errorf("un idioma sin catálogo debe quedar en español: %q", other.Message)
+	}
+
+	custom := Custom(SemRuleViolated, span, "Solo mayores de edad")
+	if got := custom.Localize(English).Message; got != "Solo mayores de edad" {
+		t.Errorf("un mensaje propio se tradujo: %q", got)
+	}
+
+	all := LocalizeAll([]Diagnostic{d, custom}, English)
+	if len(all) != 2 || all[0].Message != english.Message || all[1].Message != custom.Message {
+		t.Errorf("LocalizeAll = %+v", all)
+	}
+	if !strings.HasPrefix(d.Message, "Error semántico") {
+		t.Errorf("LocalizeAll modificó el original: %q", d.Message)
+	}
+}
+
+func TestEnglishCatalogIsComplete(t *testing.T) {
+	for code := range catalog {
+		if _, ok := english[code]; !ok {
+			t.Errorf("%s no tiene traducción al inglés", code)
+		}
+	}
+	for code := range english {
+		if _, ok := catalog[code]; !ok {
+			t.Errorf("%s está traducido pero no existe en el catálogo", code)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Package emailcheck valida direcciones de correo electrónico: su sintaxis
+// según RFC 5322 y el dominio, contra las listas que configure cada evento.
+package emailcheck
+
+import (
+	"bufio"
+	"context"
+	_ "embed"
+	"errors"
+	"net"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// Problem identifica por qué se rechazó una dirección.
+type Problem int
+
+const (
+	OK Problem = iota
+	InvalidSyntax
+	DomainNotAllowed
+	DomainDenied
+	DisposableDomain
+	NoMailServer
+)
+
+// Policy es la configuración de dominios de un campo de correo.
+type Policy struct {
+	Allowed         []string // si no está vacía, solo se aceptan estos dominios
+	Denied          []string // dominios rechazados siempre
+	BlockDisposable bool     // rechazar dominios de correo desechable
+	CheckMX         bool     // exigir que el dominio tenga servidor de correo
+}
+
+// mxTimeout limita cuánto espera LookupMX al DNS, para que un servidor lento
+// no retenga la solicitud de registro.
+const mxTimeout = 3 * time.Second
+
+// LookupMX indica si un dominio publica registros MX. Es una variable para
+// poder sustituirla en desarrollo, donde no siempre hay DNS disponible.
+var LookupMX = func(domain string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), mxTimeout)
+	defer cancel()
+	records, err := net.DefaultResolver.LookupMX(ctx, domain)
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return len(records) > 0, nil
+}
+
+// AcceptAllMX reemplaza a LookupMX cuando no se quiere consultar el DNS.
+func AcceptAllMX(string) (bool, error) {
+	return true, nil
+}
+
+// Check valida una dirección contra la política y devuelve el primer
+// problema encontrado junto con el dominio de la dirección.
+func (p Policy) Check(address string) (Problem, string) {
+	domain, ok := Domain(address)
+	if !ok {
+		return InvalidSyntax, ""
+	}
+
+	switch {
+	case len(p.Allowed) > 0 && !matchesAny(domain, p.Allowed):
+		return DomainNotAllowed, domain
+	case matchesAny(domain, p.Denied):
+		return DomainDenied, domain
+	case p.BlockDisposable && IsDisposable(domain):
+		return DisposableDomain, domain
+	}
+
+	if p.CheckMX && LookupMX != nil {
+		// Si el DNS falla no se culpa al participante: solo se rechaza
+		// cuando se sabe que el dominio no recibe correo.
+		if found, err := LookupMX(domain); err == nil && !found {
+			return NoMailServer, domain
+		}
+	}
+	return OK, domain
+}
+
+// Domain valida la sintaxis de una dirección simple (sin nombre ni '<>') y
+// devuelve su dominio en minúsculas.
+func Domain(address string) (string, bool) {
+	// ParseAddress también acepta la forma 'Nombre <dirección>' y quita las
+	// comillas de la parte local, así que se descartan esos casos aparte.
+	parsed, err := mail.ParseAddress(address)
+	if err != nil || parsed.Name != "" || strings.ContainsAny(address, "<>") || strings.TrimSpace(address) != address {
+		return "", false
+	}
+	at := strings.LastIndex(address, "@")
+	domain := strings.ToLower(address[at+1:])
+	// RFC 5322 admite dominios sin punto, pero no sirven para un registro público.
+	if !strings.Contains(domain, ".") || strings.HasPrefix(domain, "[") {
+		return "", false
+	}
+	return domain, true
+}
+
+// IsDisposable indica si el dominio pertenece a un servicio de correo desechable.
+func IsDisposable(domain string) bool {
+	return matchesAny(domain, disposable)
+}
+
+// matchesAny indica si domain es alguno de los dominios de la lista o un
+// subdominio suyo.
+func matchesAny(domain string, domains []string) bool {
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimPrefix(d, "@"))
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+//go:embed disposable.txt
+var disposableList string
+
+var disposable = parseList(disposableList)
+
+// parseList lee una lista de dominios, uno por línea, ignorando los
+// comentarios con '#' y las líneas vacías.
+func parseList(content string) []string {
+	var domains []string
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		domains = append(domains, strings.ToLower(line))
+	}
+	return domains
+}
@@ -0,0 +1,146 @@
+package emailcheck
+
+import (
+	"errors"
+	"testing"
+)
+
+// stubMX sustituye a LookupMX durante la prueba por un DNS falso.
+func stubMX(t *testing.T, lookup func(domain string) (bool, error)) {
+	t.Helper()
+	original := LookupMX
+	LookupMX = lookup
+	t.Cleanup(func() { LookupMX = original })
+}
+
+func TestDomain(t *testing.T) {
+	tests := []struct {
+		address string
+		domain  string
+		ok      bool
+	}{
+		{"ana@unam.mx", "unam.mx", true},
+		{"Ana.Lopez+carrera@Ciencias.UNAM.mx", "ciencias.unam.mx", true},
+		{"o'brien@example.com", "example.com", true},
+		{"a!#$%&*=?^_`{|}~-@example.org", "example.org", true},
+		{`"ana lopez"@example.com`, "example.com", true},
+		{"", "", false},
+		{"ana", "", false},
+		{"ana@", "", false},
+		{"@unam.mx", "", false},
+		{"ana@@unam.mx", "", false},
+		{"ana..lopez@unam.mx", "", false},
+		{".ana@unam.mx", "", false},
+		{"ana @unam.mx", "", false},
+		{" ana@unam.mx", "", false},
+		{"ana@unam.mx ", "", false},
+		{"Ana <ana@unam.mx>", "", false},
+		{"<ana@unam.mx>", "", false},
+		{"ana@localhost", "", false},
+		{"ana@[192.168.0.1]", "", false},
+		{"ana@unam.mx, luis@unam.mx", "", false},
+	}
+	for _, tt := range tests {
+		domain, ok := Domain(tt.address)
+		if domain != tt.domain || ok != tt.ok {
+			t.Errorf("Domain(%q) = %q, %v; se esperaba %q, %v", tt.address, domain, ok, tt.domain, tt.ok)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	stubMX(t, func(domain string) (bool, error) {
+		t.Errorf("Check consultó el DNS para %s sin CheckMX", domain)
+		return true, nil
+	})
+
+	restricted := Policy{
+		Allowed: []string{"unam.mx", "@IPN.mx"},
+		Denied:  []string{"alumnos.unam.mx"},
+	}
+	open := Policy{Denied: []string{"example.com"}, BlockDisposable: true}
+	tests := []struct {
+		name    string
+		policy  Policy
+		address string
+		problem Problem
+		domain  string
+	}{
+		{"sin restricciones", Policy{}, "ana@mailinator.com", OK, "mailinator.com"},
+		{"sintaxis", restricted, "ana@", InvalidSyntax, ""},
+		{"dominio permitido", restricted, "ana@unam.mx", OK, "unam.mx"},
+		{"subdominio permitido", restricted, "ana@ciencias.unam.mx", OK, "ciencias.unam.mx"},
+		{"permitido con @ y mayúsculas", restricted, "ana@ipn.MX", OK, "ipn.mx"},
+		{"fuera de la lista", restricted, "ana@gmail.com", DomainNotAllowed, "gmail.com"},
+		{"sufijo que no es subdominio", restricted, "ana@falsounam.mx", DomainNotAllowed, "falsounam.mx"},
+		{"denegado dentro de los permitidos", restricted, "ana@alumnos.unam.mx", DomainDenied, "alumnos.unam.mx"},
+		{"subdominio denegado", restricted, "ana@fi.alumnos.unam.mx", DomainDenied, "fi.alumnos.unam.mx"},
+		{"denegado", open, "ana@example.com", DomainDenied, "example.com"},
+		{"desechable", open, "ana@mailinator.com", DisposableDomain, "mailinator.com"},
+		{"subdominio desechable", open, "ana@x.10minutemail.com", DisposableDomain, "x.10minutemail.com"},
+		{"no desechable", open, "ana@gmail.com", OK, "gmail.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem, domain := tt.policy.Check(tt.address)
+			if problem != tt.problem || domain != tt.domain {
+				t.Errorf("Check(%q) = %v, %q; se esperaba %v, %q", tt.address, problem, domain, tt.problem, tt.domain)
+			}
+		})
+	}
+}
+
+func TestIsDisposable(t *testing.T) {
+	if len(disposable) == 0 {
+		t.Fatal("la lista de dominios desechables está vacía")
+	}
+	for _, domain := range disposable {
+		if domain == "" || domain[0] == '#' {
+			t.Errorf("entrada inválida en disposable.txt: %q", domain)
+		}
+	}
+	if !IsDisposable("mailinator.com") || IsDisposable("unam.mx") {
+		t.Error("IsDisposable no distingue mailinator.com de unam.mx")
+	}
+}
+
+func TestCheckMX(t *testing.T) {
+	dnsDown := errors.New("sin respuesta del DNS")
+	stubMX(t, func(domain string) (bool, error) {
+		switch domain {
+		case "unam.mx":
+			return true, nil
+		case "caido.mx":
+			return false, dnsDown
+		default:
+			return false, nil
+		}
+	})
+
+	policy := Policy{CheckMX: true, BlockDisposable: true}
+	tests := []struct {
+		address string
+		problem Problem
+	}{
+		{"ana@unam.mx", OK},
+		{"ana@sin-correo.mx", NoMailServer},
+		// Si el DNS falla no se rechaza al participante
+		{"ana@caido.mx", OK},
+		// Las listas se revisan antes de consultar el DNS
+		{"ana@mailinator.com", DisposableDomain},
+	}
+	for _, tt := range tests {
+		if problem, _ := policy.Check(tt.address); problem != tt.problem {
+			t.Errorf("Check(%q) = %v, se esperaba %v", tt.address, problem, tt.problem)
+		}
+	}
+
+	LookupMX = nil
+	if problem, _ := policy.Check("ana@sin-correo.mx"); problem != OK {
+		t.Errorf("sin LookupMX, Check = %v; se esperaba OK", problem)
+	}
+	LookupMX = AcceptAllMX
+	if problem, _ := policy.Check("ana@sin-correo.mx"); problem != OK {
+		t.Errorf("con AcceptAllMX, Check = %v; se esperaba OK", problem)
+	}
+}
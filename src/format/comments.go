@@ -0,0 +1,94 @@
+package format
+
+import (
+	"compilerciclista/src/token"
+	"strings"
+)
+
+// commentSet reparte los comentarios del documento entre los elementos que
+// se van escribiendo. Cada comentario se toma una sola vez.
+type commentSet struct {
+	comments []token.Token
+	next     int // índice del primer comentario aún no escrito
+}
+
+func newCommentSet(comments []token.Token) *commentSet {
+	return &commentSet{comments: comments}
+}
+
+// takeBefore toma los comentarios que empiezan antes de pos.
+func (c *commentSet) takeBefore(pos token.Position) []token.Token {
+	start := c.next
+	for c.next < len(c.comments) && c.comments[c.next].Span.Start.Offset < pos.Offset {
+		c.next++
+	}
+	return c.comments[start:c.next]
+}
+
+// takeSameLine toma los comentarios que siguen a pos en su misma línea,
+// sin pasar de limit (el inicio del siguiente elemento, si lo hay).
+func (c *commentSet) takeSameLine(pos, limit token.Position) []token.Token {
+	start := c.next
+	for c.next < len(c.comments) {
+		comment := c.comments[c.next]
+		if comment.Span.Start.Line != pos.Line || limit.IsValid() && comment.Span.Start.Offset >= limit.Offset {
+			break
+		}
+		c.next++
+	}
+	return c.comments[start:c.next]
+}
+
+// remaining toma todos los comentarios que quedan.
+func (c *commentSet) remaining() []token.Token {
+	rest := c.comments[c.next:]
+	c.next = len(c.comments)
+	return rest
+}
+
+// writeHeader escribe los comentarios iniciales que terminan antes de una
+// línea en blanco: describen al documento y no a una declaración, así que
+// permanecen arriba aunque las declaraciones se reordenen. Los comentarios
+// pegados al primer elemento se quedan con él.
+func (c *commentSet) writeHeader(out *strings.Builder, first token.Position, prefix string) {
+	header := 0
+	for i := c.next; i < len(c.comments); i++ {
+		comment := c.comments[i]
+		if comment.Span.Start.Offset >= first.Offset {
+			break
+		}
+		// El siguiente elemento es el próximo comentario o, si no lo hay
+		// antes del primer elemento, el primer elemento mismo.
+		next := first
+		if i+1 < len(c.comments) && c.comments[i+1].Span.Start.Offset < first.Offset {
+			next = c.comments[i+1].Span.Start
+		}
+		if next.Line > comment.Span.End.Line+1 {
+			header = i - c.next + 1
+		}
+	}
+	if header == 0 {
+		return
+	}
+
+	for _, comment := range c.comments[c.next : c.next+header] {
+		out.WriteString(prefix + comment.Literal + "\n")
+	}
+	out.WriteString("\n")
+	c.next += header
+}
+
+// writeLeading escribe, cada uno en su línea, los comentarios anteriores a pos.
+func (c *commentSet) writeLeading(out *strings.Builder, pos token.Position, prefix string) {
+	for _, comment := range c.takeBefore(pos) {
+		out.WriteString(prefix + comment.Literal + "\n")
+	}
+}
+
+// writeTrailing escribe al final de la línea actual los comentarios que
+// estaban en la misma línea que pos, sin pasar de limit (si es válida).
+func (c *commentSet) writeTrailing(out *strings.Builder, pos, limit token.Position) {
+	for _, comment := range c.takeSameLine(pos, limit) {
+		out.WriteString(" " + comment.Literal)
+	}
+}
@@ -0,0 +1,162 @@
+package format
+
+import (
+	"compilerciclista/src/ast"
+	"compilerciclista/src/diagnostic"
+	"compilerciclista/src/lexer"
+	"compilerciclista/src/models"
+	"compilerciclista/src/parser"
+	"compilerciclista/src/token"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// indent es la sangría de las declaraciones dentro de un bloque 'participante'.
+const indent = "    "
+
+// Source reescribe un documento DSL en forma canónica. Si el documento tiene
+// errores léxicos o sintácticos no se reescribe y se devuelven los diagnósticos.
+func Source(input string) (string, []diagnostic.Diagnostic) {
+	p := parser.New(lexer.New(input))
+	doc, errors := p.ParseDocument()
+	if diagnostic.HasErrors(errors) {
+		return "", errors
+	}
+	return Document(doc), nil
+}
+
+// Document escribe el árbol en forma canónica:
+//   - una declaración por línea, con las claves en el orden de models.Participant
+//     seguidas de las demás en su orden original;
+//   - cadenas con comillas dobles y solo los escapes necesarios;
+//   - bloques sangrados y separados por una línea en blanco;
+//   - los comentarios acompañan a la declaración o bloque junto al que estaban.
+func Document(doc *ast.Document) string {
+	var out strings.Builder
+	c := newCommentSet(doc.Comments)
+
+	if doc.HasBlocks() {
+		first := doc.Blocks[0].Span().Start
+		c.writeHeader(&out, first, "")
+		for i, record := range doc.Blocks {
+			if i > 0 {
+				out.WriteString("\n")
+			}
+			c.writeLeading(&out, record.Span().Start, "")
+			// Tras la '{' solo van los comentarios anteriores a la primera
+			// declaración (o a la '}' si el bloque está vacío) de esa línea.
+			body := record.RBrace.Span.Start
+			if len(record.Statements) > 0 {
+				body = record.Statements[0].Span().Start
+			}
+			out.WriteString(record.Token.Literal + " {")
+			c.writeTrailing(&out, record.Token.Span.End, body)
+			out.WriteString("\n")
+			writeStatements(&out, c, record.Statements, record.RBrace.Span.Start, indent)
+			c.writeLeading(&out, record.RBrace.Span.Start, indent)
+			out.WriteString("}")
+			c.writeTrailing(&out, record.RBrace.Span.End, token.Position{})
+			out.WriteString("\n")
+		}
+	} else {
+		if len(doc.Statements) > 0 {
+			c.writeHeader(&out, doc.Statements[0].Span().Start, "")
+		}
+		writeStatements(&out, c, doc.Statements, token.Position{}, "")
+	}
+
+	// Comentarios después del último elemento del documento
+	if rest := c.remaining(); len(rest) > 0 {
+		if out.Len() > 0 {
+			out.WriteString("\n")
+		}
+		for _, comment := range rest {
+			out.WriteString(comment.Literal + "\n")
+		}
+	}
+
+	return out.String()
+}
+
+// writeStatements escribe las declaraciones en orden canónico. Los comentarios
+// se asignan antes de reordenar, para que se muevan junto con su declaración.
+// end es el inicio de lo que sigue a la última declaración (la '}' del
+// bloque), o una posición vacía al final del documento.
+func writeStatements(out *strings.Builder, c *commentSet, statements []*ast.Statement, end token.Position, prefix string) {
+	type entry struct {
+		stmt     *ast.Statement
+		leading  []token.Token
+		trailing []token.Token
+	}
+
+	entries := make([]entry, len(statements))
+	for i, stmt := range statements {
+		next := end
+		if i+1 < len(statements) {
+			next = statements[i+1].Span().Start
+		}
+		entries[i] = entry{
+			stmt:     stmt,
+			leading:  c.takeBefore(stmt.Span().End),
+			trailing: c.takeSameLine(stmt.Span().End, next),
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return keyRank(entries[i].stmt.Key.Name) < keyRank(entries[j].stmt.Key.Name)
+	})
+
+	for _, e := range entries {
+		for _, comment := range e.leading {
+			out.WriteString(prefix + comment.Literal + "\n")
+		}
+		out.WriteString(prefix + e.stmt.Key.Name + ": " + formatValue(e.stmt.Value) + ";")
+		for _, comment := range e.trailing {
+			out.WriteString(" " + comment.Literal)
+		}
+		out.WriteString("\n")
+	}
+}
+
+// formatValue escribe un valor normalizando las comillas de las cadenas.
+// Los números y fechas conservan su texto original.
+func formatValue(value ast.Value) string {
+	switch v := value.(type) {
+	case *ast.StringLiteral:
+		return ast.Quote(v.Value)
+	case *ast.ListLiteral:
+		items := make([]string, len(v.Elements))
+		for i, e := range v.Elements {
+			items[i] = formatValue(e)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	default:
+		return value.String()
+	}
+}
+
+// canonicalOrder es el orden de las claves según los campos de models.Participant.
+// Se obtiene de las etiquetas json del modelo para no repetir la lista aquí.
+var canonicalOrder = participantFieldOrder()
+
+func participantFieldOrder() map[string]int {
+	order := make(map[string]int)
+	t := reflect.TypeOf(models.Participant{})
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" || name == "id" || name == "participant_code" {
+			continue // no se escriben en el DSL
+		}
+		order[name] = len(order)
+	}
+	return order
+}
+
+// keyRank ubica primero las claves del modelo y después todas las demás.
+func keyRank(key string) int {
+	if rank, ok := canonicalOrder[key]; ok {
+		return rank
+	}
+	return len(canonicalOrder)
+}
@@ -0,0 +1,94 @@
+package format
+
+import "testing"
+
+func TestSourceHeaderComments(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "comentarios pegados a la primera declaración",
+			input: "# Registro de Ana\n# club Tigres\nnombre: \"a\";\n",
+			want:  "# Registro de Ana\n# club Tigres\nnombre: \"a\";\n",
+		},
+		{
+			name:  "encabezado separado por una línea en blanco",
+			input: "# h\n\nemail: \"a@b.mx\";\nnombre: \"a\";\n",
+			want:  "# h\n\nnombre: \"a\";\nemail: \"a@b.mx\";\n",
+		},
+		{
+			name:  "encabezado y comentario de la declaración",
+			input: "# h\n\n# x\nnombre: /* y */ \"a\";\n",
+			want:  "# h\n\n# x\n/* y */\nnombre: \"a\";\n",
+		},
+		{
+			name:  "varios párrafos de encabezado",
+			input: "# a\n\n# b\n\nnombre: \"a\";\n",
+			want:  "# a\n# b\n\nnombre: \"a\";\n",
+		},
+		{
+			name:  "bloque en una línea con comentario final",
+			input: "participante { email: \"a@b.mx\"; nombre: \"b\"; } # fin\n",
+			want:  "participante {\n    nombre: \"b\";\n    email: \"a@b.mx\";\n} # fin\n",
+		},
+		{
+			name:  "bloque en una línea con comentarios dentro y fuera",
+			input: "participante { /* a */ nombre: \"b\"; /* b */ } /* c */ # fin\n",
+			want:  "participante { /* a */\n    nombre: \"b\"; /* b */\n} /* c */ # fin\n",
+		},
+		{
+			name:  "bloque vacío en una línea",
+			input: "participante { /* vacío */ } # fin\nparticipante { nombre: \"b\"; }\n",
+			want:  "participante { /* vacío */\n} # fin\n\nparticipante {\n    nombre: \"b\";\n}\n",
+		},
+		{
+			name:  "encabezado antes de los bloques",
+			input: "# Club Tigres\n\n# Ana\nparticipante {\n nombre: \"a\";\n}\n",
+			want:  "# Club Tigres\n\n# Ana\nparticipante {\n    nombre: \"a\";\n}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, diags := Source(tt.input)
+			if len(diags) > 0 {
+				t.Fatalf("Source(%q) devolvió diagnósticos: %v", tt.input, diags)
+			}
+			if got != tt.want {
+				t.Errorf("Source(%q) =\n%s\nse esperaba\n%s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSourceIdempotent(t *testing.T) {
+	inputs := []string{
+		"email: \"ana@unam.mx\"; nombre: \"Ana\";",
+		"# Registro de Ana\n# club Tigres\nnombre: \"a\";",
+		"# h\n\n# x\nnombre: /* y */ \"a\";",
+		"# a\n\n# b\n\n\nnombre: \"a\"; # final\n",
+		"email: \"a@b.mx\"; /* correo */\n# nombre\nnombre: \"a\";\n# al final",
+		"participante { nombre: \"a\"; } participante { /* vacío */ email: \"b@c.mx\"; }",
+		"participante { email: \"a@b.mx\"; nombre: \"b\"; } # fin",
+		"participante { /* a */ nombre: \"b\"; /* b */ } /* c */ # fin\nparticipante { } # vacío",
+		"# encabezado\n\nparticipante {\n  # ana\n  nombre: \"Ana\";\n} # fin\n",
+		"nombre: \"Ana \\\"la flecha\\\"\"; apellido_paterno: \"P\\u00e9rez\\t\";",
+		"fecha_nacimiento: 1990-05-01; pago_realizado: true; categorias: [\"a\", \"b\"];",
+	}
+
+	for _, input := range inputs {
+		once, diags := Source(input)
+		if len(diags) > 0 {
+			t.Fatalf("Source(%q) devolvió diagnósticos: %v", input, diags)
+		}
+		twice, diags := Source(once)
+		if len(diags) > 0 {
+			t.Fatalf("Source(Source(%q)) devolvió diagnósticos: %v", input, diags)
+		}
+		if twice != once {
+			t.Errorf("Source no es idempotente para %q:\nprimera vez:\n%s\nsegunda vez:\n%s", input, once, twice)
+		}
+	}
+}
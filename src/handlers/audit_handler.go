@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"compilerciclista/src/database"
+	"compilerciclista/src/models"
+	"compilerciclista/src/semantic"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// History responde con la auditoría del participante cuyo código está en la
+// ruta, sin importar el estado de su inscripción.
+func (h *ParticipantHandler) History(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	participant, err := h.repo.FindByCode(semantic.ActiveSchema().Event, code)
+	if errors.Is(err, database.ErrNotFound) {
+		respondWithError(w, http.StatusNotFound, fmt.Sprintf("No existe un participante con el código '%s'.", code))
+		return
+	}
+	if err == nil {
+		var history []models.AuditEntry
+		if history, err = h.repo.History(participant.ID); err == nil {
+			respondWithJSON(w, http.StatusOK, map[string]interface{}{
+				"participant_code": participant.ParticipantCode,
+				"history":          history,
+			})
+			return
+		}
+	}
+	log.Printf("ERROR: No se pudo obtener la auditoría del participante %s: %v", code, err)
+	respondWithError(w, http.StatusInternalServerError, "No se pudo obtener el historial del participante")
+}
+
+// auditOrigin identifica de dónde viene una operación: quién la pidió y en
+// qué solicitud.
+type auditOrigin struct {
+	actor     string
+	requestID string
+}
+
+func originOf(r *http.Request) auditOrigin {
+	return auditOrigin{actor: requestActor(r), requestID: requestID(r)}
+}
+
+// requestActor identifica a quien hace la operación a partir del token JWT
+// de la solicitud. Sin un token válido, el actor es anónimo.
+func requestActor(r *http.Request) string {
+	if claims, ok := requestClaims(r); ok {
+		return claims.Actor()
+	}
+	return "anónimo"
+}
+
+// record anota en la auditoría, dentro de la transacción tx, una operación
+// sobre el participante. before es nil cuando la operación lo crea.
+func (o auditOrigin) record(tx database.ParticipantRepository, action string, before *models.Participant, after models.Participant) error {
+	entry := models.AuditEntry{
+		ParticipantID: after.ID,
+		Action:        action,
+		Actor:         o.actor,
+		RequestID:     o.requestID,
+	}
+	var err error
+	if before != nil {
+		if entry.Before, err = json.Marshal(before); err != nil {
+			return err
+		}
+	}
+	if entry.After, err = json.Marshal(after); err != nil {
+		return err
+	}
+	return tx.RecordAudit(entry)
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"compilerciclista/src/database"
+	"compilerciclista/src/models"
+	"compilerciclista/src/semantic"
+	"compilerciclista/src/services"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// AuthHandler atiende el inicio de sesión y la administración de cuentas.
+type AuthHandler struct {
+	users        database.UserRepository
+	participants database.ParticipantRepository
+}
+
+// NewAuthHandler crea los handlers de cuentas sobre users; participants se
+// usa para vincular las cuentas de corredor con su inscripción.
+func NewAuthHandler(users database.UserRepository, participants database.ParticipantRepository) *AuthHandler {
+	return &AuthHandler{users: users, participants: participants}
+}
+
+// credentials es el cuerpo JSON de POST /login y POST /users.
+type credentials struct {
+	Username        string `json:"username"`
+	Password        string `json:"password"`
+	Rol             string `json:"rol"`
+	ParticipantCode string `json:"participant_code"` // solo al crear corredores
+}
+
+// dummyHash se compara cuando el usuario no existe, para que la respuesta
+// tarde lo mismo y no revele qué cuentas hay.
+var dummyHash, _ = services.HashPassword("contraseña-inexistente")
+
+// Login recibe {"username", "password"} y, si son correctos, responde con un
+// token de acceso que lleva el rol de la cuenta.
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var request credentials
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		respondWithError(w, http.StatusBadRequest, "El cuerpo debe ser un JSON con 'username' y 'password'")
+		return
+	}
+
+	user, err := h.users.FindUser(strings.TrimSpace(request.Username))
+	if err != nil && !errors.Is(err, database.ErrUserNotFound) {
+		log.Printf("ERROR: No se pudo buscar al usuario '%s': %v", request.Username, err)
+		respondWithError(w, http.StatusInternalServerError, "No se pudo iniciar sesión")
+		return
+	}
+	if err != nil {
+		services.CheckPassword(dummyHash, request.Password)
+		respondWithError(w, http.StatusUnauthorized, "Usuario o contraseña incorrectos.")
+		return
+	}
+	if !services.CheckPassword(user.PasswordHash, request.Password) {
+		log.Printf("ADVERTENCIA: Contraseña incorrecta para el usuario '%s'.", user.Username)
+		respondWithError(w, http.StatusUnauthorized, "Usuario o contraseña incorrectos.")
+		return
+	}
+
+	token, err := services.GenerateUserToken(user)
+	if err != nil {
+		log.Printf("ERROR: No se pudo generar el token del usuario '%s': %v", user.Username, err)
+		respondWithError(w, http.StatusInternalServerError, "No se pudo generar el token de acceso JWT.")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": token,
+		"token_type":   "Bearer",
+		"user":         user,
+	})
+}
+
+// CreateUser crea una cuenta. Recibe {"username", "password", "rol"} y, para
+// los corredores, "participant_code" con su inscripción en el evento.
+func (h *AuthHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	var request credentials
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		respondWithError(w, http.StatusBadRequest, "El cuerpo debe ser un JSON con 'username', 'password' y 'rol'")
+		return
+	}
+	user, status, err := h.newUser(request)
+	if err != nil {
+		respondWithError(w, status, err.Error())
+		return
+	}
+	respondWithJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": fmt.Sprintf("Usuario '%s' creado con el rol '%s'.", user.Username, user.Rol),
+		"user":    user,
+	})
+}
+
+// Bootstrap crea la primera cuenta de administrador si todavía no hay
+// ninguna, para poder crear las demás con POST /users.
+func (h *AuthHandler) Bootstrap(username, password string) (bool, error) {
+	count, err := h.users.CountUsers()
+	if err != nil || count > 0 {
+		return false, err
+	}
+	if _, _, err := h.newUser(credentials{Username: username, Password: password, Rol: models.RolAdmin}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// newUser valida y guarda una cuenta; si falla devuelve también el código
+// HTTP con el que se debe responder.
+func (h *AuthHandler) newUser(request credentials) (models.User, int, error) {
+	user := models.User{Username: strings.TrimSpace(request.Username), Rol: request.Rol}
+	if user.Username == "" {
+		return user, http.StatusBadRequest, fmt.Errorf("Falta el campo 'username'")
+	}
+	if !models.IsRol(user.Rol) {
+		return user, http.StatusBadRequest, fmt.Errorf("El rol debe ser uno de: %s", strings.Join(models.Roles, ", "))
+	}
+
+	if user.Rol == models.RolCorredor {
+		if request.ParticipantCode == "" {
+			return user, http.StatusBadRequest, fmt.Errorf("Las cuentas de corredor necesitan el 'participant_code' de su inscripción")
+		}
+		participant, err := h.participants.FindByCode(semantic.ActiveSchema().Event, request.ParticipantCode)
+		if errors.Is(err, database.ErrNotFound) {
+			return user, http.StatusBadRequest, fmt.Errorf("No existe un participante con el código '%s'.", request.ParticipantCode)
+		}
+		if err != nil {
+			log.Printf("ERROR: No se pudo buscar al participante %s: %v", request.ParticipantCode, err)
+			return user, http.StatusInternalServerError, fmt.Errorf("No se pudo crear el usuario")
+		}
+		user.ParticipantID = participant.ID
+	}
+
+	hash, err := services.HashPassword(request.Password)
+	if err != nil {
+		return user, http.StatusBadRequest, fmt.Errorf("Contraseña no válida: %v", err)
+	}
+	user.PasswordHash = hash
+
+	user.ID, err = h.users.CreateUser(user)
+	if errors.Is(err, database.ErrDuplicate) {
+		return user, http.StatusConflict, fmt.Errorf("Conflicto de datos: El usuario '%s' ya existe.", user.Username)
+	}
+	if err != nil {
+		log.Printf("ERROR: No se pudo crear el usuario '%s': %v", user.Username, err)
+		return user, http.StatusInternalServerError, fmt.Errorf("No se pudo crear el usuario")
+	}
+	return user, 0, nil
+}
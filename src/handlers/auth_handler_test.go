@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"compilerciclista/src/database"
+	"compilerciclista/src/models"
+	"compilerciclista/src/services"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newAuthServer monta /login y /users como main.go, con una cuenta de
+// administrador creada por Bootstrap.
+func newAuthServer(t *testing.T) (*AuthHandler, *database.MemoryRepository, *http.ServeMux) {
+	t.Setenv("JWT_SECRET_KEY", "secreto-de-prueba")
+	repo := database.NewMemoryRepository()
+	auth := NewAuthHandler(repo, repo)
+	if created, err := auth.Bootstrap("admin", "contraseña-admin"); err != nil || !created {
+		t.Fatalf("Bootstrap = %v, %v", created, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /login", auth.Login)
+	mux.HandleFunc("POST /users", RequireRole(models.RolAdmin)(auth.CreateUser))
+	return auth, repo, mux
+}
+
+// postJSON envía body a path y devuelve el código y la respuesta decodificada.
+func postJSON(t *testing.T, mux *http.ServeMux, path, token, body string) (int, map[string]interface{}) {
+	t.Helper()
+	req := httptest.NewRequest("POST", path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	var payload map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("POST %s: respuesta que no es JSON: %s", path, w.Body.String())
+	}
+	return w.Code, payload
+}
+
+func TestLogin(t *testing.T) {
+	_, _, mux := newAuthServer(t)
+
+	status, payload := postJSON(t, mux, "/login", "", `{"username": "admin", "password": "contraseña-admin"}`)
+	if status != http.StatusOK {
+		t.Fatalf("login: %d %v", status, payload)
+	}
+	token, _ := payload["access_token"].(string)
+	claims, err := services.VerifyToken(token)
+	if err != nil || claims.Rol != models.RolAdmin || claims.Subject != "admin" {
+		t.Errorf("token de login: %+v, %v", claims, err)
+	}
+	if user, _ := payload["user"].(map[string]interface{}); user["password_hash"] != nil {
+		t.Errorf("la respuesta incluye el hash de la contraseña: %v", user)
+	}
+
+	_, wrongPassword := postJSON(t, mux, "/login", "", `{"username": "admin", "password": "otra-contraseña"}`)
+	status, unknownUser := postJSON(t, mux, "/login", "", `{"username": "nadie", "password": "otra-contraseña"}`)
+	if status != http.StatusUnauthorized {
+		t.Errorf("usuario desconocido: %d, se esperaba 401", status)
+	}
+	if wrongPassword["error"] != unknownUser["error"] {
+		t.Errorf("las respuestas revelan si la cuenta existe: %v y %v", wrongPassword, unknownUser)
+	}
+	status, _ = postJSON(t, mux, "/login", "", `{"username": "admin", "password": "otra-contraseña"}`)
+	if status != http.StatusUnauthorized {
+		t.Errorf("contraseña incorrecta: %d, se esperaba 401", status)
+	}
+	status, _ = postJSON(t, mux, "/login", "", `usuario=admin`)
+	if status != http.StatusBadRequest {
+		t.Errorf("cuerpo que no es JSON: %d, se esperaba 400", status)
+	}
+}
+
+func TestCreateUser(t *testing.T) {
+	_, repo, mux := newAuthServer(t)
+	_, payload := postJSON(t, mux, "/login", "", `{"username": "admin", "password": "contraseña-admin"}`)
+	admin, _ := payload["access_token"].(string)
+
+	tests := []struct {
+		name   string
+		token  string
+		body   string
+		status int
+	}{
+		{"sin token", "", `{"username": "mesa1", "password": "contraseña-mesa", "rol": "mesa_registro"}`, http.StatusUnauthorized},
+		{"rol desconocido", admin, `{"username": "x", "password": "contraseña-x", "rol": "jefe"}`, http.StatusBadRequest},
+		{"contraseña corta", admin, `{"username": "x", "password": "corta", "rol": "juez"}`, http.StatusBadRequest},
+		{"corredor sin inscripción", admin, `{"username": "ana", "password": "contraseña-ana", "rol": "corredor"}`, http.StatusBadRequest},
+		{"corredor con código inexistente", admin, `{"username": "ana", "password": "contraseña-ana", "rol": "corredor", "participant_code": "ELI-999"}`, http.StatusBadRequest},
+		{"mesa", admin, `{"username": "mesa1", "password": "contraseña-mesa", "rol": "mesa_registro"}`, http.StatusCreated},
+		{"usuario repetido", admin, `{"username": "mesa1", "password": "contraseña-mesa", "rol": "juez"}`, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		if status, payload := postJSON(t, mux, "/users", tt.token, tt.body); status != tt.status {
+			t.Errorf("%s: %d %v, se esperaba %d", tt.name, status, payload, tt.status)
+		}
+	}
+
+	_, payload = postJSON(t, mux, "/login", "", `{"username": "mesa1", "password": "contraseña-mesa"}`)
+	mesa, _ := payload["access_token"].(string)
+	status, _ := postJSON(t, mux, "/users", mesa, `{"username": "juez1", "password": "contraseña-juez", "rol": "juez"}`)
+	if status != http.StatusForbidden {
+		t.Errorf("la mesa creó una cuenta: %d, se esperaba 403", status)
+	}
+	if count, err := repo.CountUsers(); err != nil || count != 2 {
+		t.Errorf("CountUsers = %d, %v; se esperaban 2", count, err)
+	}
+}
+
+func TestBootstrapRunsOnce(t *testing.T) {
+	auth, repo, _ := newAuthServer(t)
+	created, err := auth.Bootstrap("otro-admin", "contraseña-otro")
+	if err != nil || created {
+		t.Errorf("Bootstrap con cuentas existentes = %v, %v; no debía crear nada", created, err)
+	}
+	if _, err := repo.FindUser("otro-admin"); err == nil {
+		t.Error("Bootstrap creó una segunda cuenta de administrador")
+	}
+}
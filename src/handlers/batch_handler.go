@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"compilerciclista/src/database"
+	"compilerciclista/src/diagnostic"
+	"compilerciclista/src/lexer"
+	"compilerciclista/src/models"
+	"compilerciclista/src/parser"
+	"compilerciclista/src/semantic"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// Estados posibles de cada bloque en la respuesta de un lote.
+const (
+	batchRegistered = "registrado"
+	batchInvalid    = "invalido"
+	batchFailed     = "error"
+	batchSkipped    = "no_procesado" // lote atómico cancelado por otro bloque
+)
+
+// RegisterBatchHandler registra varios participantes descritos en un solo
+// documento con bloques 'participante { ... }'. Cada bloque se valida por
+// separado y la respuesta informa el resultado de cada uno.
+//
+// Con ?atomic=true el lote es todo o nada: si algún bloque es inválido o falla
+// al guardarse, no se registra ninguno.
+func (h *ParticipantHandler) RegisterBatch(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "No se pudo leer el cuerpo de la solicitud")
+		return
+	}
+
+	atomic := false
+	if value := r.URL.Query().Get("atomic"); value != "" {
+		if atomic, err = strconv.ParseBool(value); err != nil {
+			respondWithError(w, http.StatusBadRequest, "El parámetro 'atomic' debe ser true o false")
+			return
+		}
+	}
+
+	// Pipeline del Compilador sobre el documento completo
+	p := parser.New(lexer.New(string(body)))
+	records, parsingErrors := p.ParseBatch()
+	if len(parsingErrors) > 0 {
+		respondWithDiagnostics(w, r, http.StatusBadRequest, parsingErrors)
+		return
+	}
+	if len(records) == 0 {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("El documento no contiene bloques '%s'.", parser.RecordKeyword))
+		return
+	}
+
+	// Validación semántica de cada bloque
+	results := make([]map[string]interface{}, len(records))
+	participants := make([]models.Participant, len(records))
+	valid := make([]bool, len(records))
+	invalidCount := 0
+	curps := make(map[string]int) // CURP -> índice del bloque que la usó primero
+	for i, record := range records {
+		results[i] = map[string]interface{}{
+			"index": i + 1,
+			"line":  record.Span.Start.Line,
+		}
+		semanticErrors := semantic.Analyze(record.Data, record.Spans)
+		if diagnostic.HasErrors(semanticErrors) {
+			results[i]["status"] = batchInvalid
+			results[i]["diagnostics"] = localize(r, semanticErrors)
+			invalidCount++
+			continue
+		}
+		if len(semanticErrors) > 0 {
+			results[i]["warnings"] = localize(r, semanticErrors)
+		}
+		participantModel, err := populateModel(record.Data)
+		if err != nil {
+			markFailed(results[i], batchInvalid, err.Error())
+			invalidCount++
+			continue
+		}
+		if first, repeated := curps[participantModel.CURP]; repeated {
+			markFailed(results[i], batchInvalid, fmt.Sprintf("La CURP '%s' ya se usó en el bloque %d del documento.", participantModel.CURP, first))
+			invalidCount++
+			continue
+		} else if participantModel.CURP != "" {
+			curps[participantModel.CURP] = i + 1
+		}
+		participants[i] = participantModel
+		valid[i] = true
+	}
+
+	if atomic {
+		h.registerBatchAtomically(w, originOf(r), results, participants, invalidCount)
+		return
+	}
+
+	// Sin atomicidad cada bloque válido se registra por su cuenta
+	for i := range records {
+		if !valid[i] {
+			continue
+		}
+		participantModel, regErr := h.register(participants[i], originOf(r))
+		if regErr != nil {
+			markFailed(results[i], batchFailed, regErr.message)
+			continue
+		}
+		markRegistered(results[i], participantModel)
+	}
+
+	respondWithBatch(w, results, false)
+}
+
+// registerBatchAtomically guarda todos los bloques válidos en una sola
+// transacción y solo notifica a los participantes cuando se confirma.
+func (h *ParticipantHandler) registerBatchAtomically(w http.ResponseWriter, origin auditOrigin, results []map[string]interface{}, participants []models.Participant, invalidCount int) {
+	if invalidCount > 0 {
+		skipPending(results)
+		respondWithBatchStatus(w, http.StatusBadRequest, results, true)
+		return
+	}
+
+	stored := make([]models.Participant, len(participants))
+	failed := -1 // bloque que canceló la transacción
+	var regErr *registrationError
+	err := h.repo.WithTx(func(tx database.ParticipantRepository) error {
+		for i := range participants {
+			stored[i], regErr = storeParticipant(tx, participants[i], origin)
+			if regErr != nil {
+				failed = i
+				return regErr
+			}
+		}
+		return nil
+	})
+	if regErr != nil {
+		markFailed(results[failed], batchFailed, regErr.message)
+		skipPending(results)
+		respondWithBatchStatus(w, regErr.status, results, true)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "No se pudo confirmar la transacción del lote")
+		return
+	}
+
+	for i := range stored {
+		markRegistered(results[i], stored[i])
+	}
+	respondWithBatch(w, results, true)
+}
+
+// markRegistered anota en result el participante guardado y lo notifica.
+func markRegistered(result map[string]interface{}, participantModel models.Participant) {
+	result["status"] = batchRegistered
+	result["participant"] = participantModel
+	notifyParticipant(participantModel, result)
+}
+
+func markFailed(result map[string]interface{}, status string, message string) {
+	result["status"] = status
+	result["error"] = message
+}
+
+// skipPending marca como no procesados los bloques que aún no tienen estado.
+func skipPending(results []map[string]interface{}) {
+	for _, result := range results {
+		if _, done := result["status"]; !done {
+			result["status"] = batchSkipped
+		}
+	}
+}
+
+// respondWithBatch responde 201 si todos los bloques se registraron y 207
+// (Multi-Status) si el resultado fue parcial.
+func respondWithBatch(w http.ResponseWriter, results []map[string]interface{}, atomic bool) {
+	code := http.StatusCreated
+	for _, result := range results {
+		if result["status"] != batchRegistered {
+			code = http.StatusMultiStatus
+			break
+		}
+	}
+	respondWithBatchStatus(w, code, results, atomic)
+}
+
+func respondWithBatchStatus(w http.ResponseWriter, code int, results []map[string]interface{}, atomic bool) {
+	registered := 0
+	for _, result := range results {
+		if result["status"] == batchRegistered {
+			registered++
+		}
+	}
+
+	message := fmt.Sprintf("Se registraron %d de %d participantes.", registered, len(results))
+	if atomic && registered == 0 {
+		message = "El lote no se registró: ningún participante fue guardado."
+	}
+
+	respondWithJSON(w, code, map[string]interface{}{
+		"message":    message,
+		"atomic":     atomic,
+		"total":      len(results),
+		"registered": registered,
+		"failed":     len(results) - registered,
+		"results":    results,
+	})
+}
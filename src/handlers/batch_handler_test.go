@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"compilerciclista/src/database"
+	"compilerciclista/src/semantic"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// batch arma un documento con un bloque 'participante' por cuerpo.
+func batch(bodies ...string) string {
+	var doc strings.Builder
+	for _, body := range bodies {
+		doc.WriteString("participante {\n    " + body + "\n}\n")
+	}
+	return doc.String()
+}
+
+// batchStatuses devuelve el estado de cada bloque de la respuesta.
+func batchStatuses(t *testing.T, payload map[string]interface{}) []string {
+	t.Helper()
+	results, ok := payload["results"].([]interface{})
+	if !ok {
+		t.Fatalf("la respuesta no trae resultados: %v", payload)
+	}
+	statuses := make([]string, len(results))
+	for i, result := range results {
+		statuses[i], _ = result.(map[string]interface{})["status"].(string)
+	}
+	return statuses
+}
+
+func (s *testServer) stored() int64 {
+	s.t.Helper()
+	_, total, err := s.repo.List(database.ParticipantFilter{Evento: semantic.ActiveSchema().Event, Limit: 10})
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	return total
+}
+
+func TestRegisterBatch(t *testing.T) {
+	s := newTestServer(t)
+	doc := batch(
+		registration("ana@unam.mx"),
+		`nombre: "Eva"; sexo: "X";`,
+		registration("ANA@unam.mx"), // el mismo email que el primer bloque
+		registration("luis@unam.mx"),
+	)
+
+	status, payload := s.do("POST", "/register/batch", s.token("mesa_registro"), doc)
+	if status != http.StatusMultiStatus {
+		t.Fatalf("lote parcial: %d %v", status, payload)
+	}
+	want := []string{batchRegistered, batchInvalid, batchFailed, batchRegistered}
+	if got := batchStatuses(t, payload); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("estados = %v, se esperaba %v", got, want)
+	}
+	if payload["registered"] != 2.0 || payload["failed"] != 2.0 || payload["atomic"] != false {
+		t.Errorf("resumen del lote: %v", payload)
+	}
+
+	results := payload["results"].([]interface{})
+	invalid := results[1].(map[string]interface{})
+	if invalid["index"] != 2.0 || invalid["line"] != 4.0 || invalid["diagnostics"] == nil {
+		t.Errorf("bloque inválido: %v", invalid)
+	}
+	if failed := results[2].(map[string]interface{}); !strings.Contains(failed["error"].(string), "email") {
+		t.Errorf("bloque con email repetido: %v", failed)
+	}
+	if p := s.find("ELI-002"); p.Email != "luis@unam.mx" {
+		t.Errorf("el cuarto bloque se guardó como %+v", p)
+	}
+	if n := s.stored(); n != 2 {
+		t.Errorf("se guardaron %d participantes, se esperaban 2", n)
+	}
+
+	status, payload = s.do("POST", "/register/batch", s.token("mesa_registro"), batch(registration("eva@unam.mx")))
+	if status != http.StatusCreated || payload["registered"] != 1.0 {
+		t.Errorf("lote completo: %d %v", status, payload)
+	}
+}
+
+func TestRegisterBatchAtomic(t *testing.T) {
+	tests := []struct {
+		name   string
+		doc    string
+		status int
+		want   []string
+	}{
+		{
+			"email repetido dentro del lote",
+			batch(registration("ana@unam.mx"), registration("luis@unam.mx"), registration("ana@unam.mx"), registration("eva@unam.mx")),
+			http.StatusConflict,
+			[]string{batchSkipped, batchSkipped, batchFailed, batchSkipped},
+		},
+		{
+			"bloque inválido",
+			batch(registration("ana@unam.mx"), `nombre: "Eva"; sexo: "X";`),
+			http.StatusBadRequest,
+			[]string{batchSkipped, batchInvalid},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			status, payload := s.do("POST", "/register/batch?atomic=true", s.token("mesa_registro"), tt.doc)
+			if status != tt.status {
+				t.Fatalf("respondió %d %v, se esperaba %d", status, payload, tt.status)
+			}
+			if got := batchStatuses(t, payload); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("estados = %v, se esperaba %v", got, tt.want)
+			}
+			if payload["registered"] != 0.0 || payload["atomic"] != true {
+				t.Errorf("resumen del lote: %v", payload)
+			}
+			if n := s.stored(); n != 0 {
+				t.Errorf("el lote cancelado guardó %d participantes", n)
+			}
+
+			// La transacción descartada tampoco consumió números de la secuencia
+			s.register(registration("nuevo@unam.mx"))
+			if p := s.find("ELI-001"); p.Email != "nuevo@unam.mx" {
+				t.Errorf("el siguiente registro es %+v", p)
+			}
+		})
+	}
+}
+
+func TestRegisterBatchRequest(t *testing.T) {
+	s := newTestServer(t)
+	tests := []struct {
+		name string
+		path string
+		doc  string
+	}{
+		{"atomic inválido", "/register/batch?atomic=quizá", batch(registration("ana@unam.mx"))},
+		{"sin bloques", "/register/batch", ""},
+		{"bloque mal escrito", "/register/batch", `participante { nombre "Ana" }`},
+	}
+	for _, tt := range tests {
+		if status, payload := s.do("POST", tt.path, s.token("mesa_registro"), tt.doc); status != http.StatusBadRequest {
+			t.Errorf("%s: %d %v, se esperaba 400", tt.name, status, payload)
+		}
+	}
+	if n := s.stored(); n != 0 {
+		t.Errorf("se guardaron %d participantes", n)
+	}
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"compilerciclista/src/format"
+	"io"
+	"net/http"
+)
+
+// FormatHandler devuelve el documento DSL recibido reescrito en forma canónica.
+// No registra nada: sirve a los clientes para normalizar sus documentos.
+func FormatHandler(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "No se pudo leer el cuerpo de la solicitud")
+		return
+	}
+
+	formatted, errors := format.Source(string(body))
+	if len(errors) > 0 {
+		respondWithDiagnostics(w, r, http.StatusBadRequest, errors)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"formatted": formatted,
+		"changed":   formatted != string(body),
+	})
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"compilerciclista/src/database"
+	"compilerciclista/src/models"
+	"compilerciclista/src/parser"
+	"compilerciclista/src/semantic"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tamaño de página de GET /participants.
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// List responde con una página de los participantes del evento. Acepta los
+// parámetros page, page_size, categoria, sexo, pago_realizado, created_from y
+// created_to (AAAA-MM-DD, ambos inclusive; los días son en UTC, como las
+// fechas de registro guardadas), q (búsqueda en nombres y email) y estado;
+// sin estado solo lista las inscripciones activas.
+func (h *ParticipantHandler) List(w http.ResponseWriter, r *http.Request) {
+	filter, page, err := parseListFilter(r.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	filter.Evento = semantic.ActiveSchema().Event
+
+	participants, total, err := h.repo.List(filter)
+	if err != nil {
+		log.Printf("ERROR: No se pudo listar a los participantes: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "No se pudo obtener la lista de participantes")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"participants": participants,
+		"page":         page,
+		"page_size":    filter.Limit,
+		"total":        total,
+	})
+}
+
+// Get responde con el participante del evento cuyo código está en la ruta.
+func (h *ParticipantHandler) Get(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	participant, err := h.repo.FindByCode(semantic.ActiveSchema().Event, code)
+	if errors.Is(err, database.ErrNotFound) {
+		respondWithError(w, http.StatusNotFound, fmt.Sprintf("No existe un participante con el código '%s'.", code))
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: No se pudo buscar al participante %s: %v", code, err)
+		respondWithError(w, http.StatusInternalServerError, "No se pudo obtener el participante")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, participant)
+}
+
+// parseListFilter convierte los parámetros de la URL en un filtro y devuelve
+// también el número de página pedido.
+func parseListFilter(query url.Values) (database.ParticipantFilter, int, error) {
+	filter := database.ParticipantFilter{
+		Categoria: query.Get("categoria"),
+		Sexo:      query.Get("sexo"),
+		Estado:    query.Get("estado"),
+		Search:    query.Get("q"),
+		Limit:     defaultPageSize,
+	}
+
+	if filter.Estado != "" && !models.IsEstado(filter.Estado) {
+		return filter, 0, fmt.Errorf("El parámetro 'estado' debe ser uno de: %s", strings.Join(models.Estados, ", "))
+	}
+
+	page := 1
+	if value := query.Get("page"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return filter, 0, fmt.Errorf("El parámetro 'page' debe ser un número mayor que cero")
+		}
+		page = n
+	}
+	if value := query.Get("page_size"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxPageSize {
+			return filter, 0, fmt.Errorf("El parámetro 'page_size' debe ser un número entre 1 y %d", maxPageSize)
+		}
+		filter.Limit = n
+	}
+	filter.Offset = (page - 1) * filter.Limit
+
+	if value := query.Get("pago_realizado"); value != "" {
+		paid, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, 0, fmt.Errorf("El parámetro 'pago_realizado' debe ser true o false")
+		}
+		filter.PagoRealizado = &paid
+	}
+
+	for _, param := range []struct {
+		name   string
+		target **time.Time
+		days   int // created_to incluye el día completo
+	}{{"created_from", &filter.CreatedFrom, 0}, {"created_to", &filter.CreatedTo, 1}} {
+		value := query.Get(param.name)
+		if value == "" {
+			continue
+		}
+		date, err := time.Parse(parser.DateLayout, value) // en UTC
+		if err != nil {
+			return filter, 0, fmt.Errorf("El parámetro '%s' debe ser una fecha AAAA-MM-DD", param.name)
+		}
+		date = date.AddDate(0, 0, param.days)
+		*param.target = &date
+	}
+
+	return filter, page, nil
+}
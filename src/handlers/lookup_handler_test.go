@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestParseListFilterDatesInUTC(t *testing.T) {
+	query := url.Values{"created_from": {"2026-05-01"}, "created_to": {"2026-05-03"}}
+	filter, _, err := parseListFilter(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantFrom := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	wantTo := time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC) // created_to incluye el día completo
+	if filter.CreatedFrom == nil || !filter.CreatedFrom.Equal(wantFrom) || filter.CreatedFrom.Location() != time.UTC {
+		t.Errorf("CreatedFrom = %v, se esperaba %v", filter.CreatedFrom, wantFrom)
+	}
+	if filter.CreatedTo == nil || !filter.CreatedTo.Equal(wantTo) || filter.CreatedTo.Location() != time.UTC {
+		t.Errorf("CreatedTo = %v, se esperaba %v", filter.CreatedTo, wantTo)
+	}
+
+	if _, _, err := parseListFilter(url.Values{"created_to": {"01/05/2026"}}); err == nil {
+		t.Error("se esperaba un error para una fecha que no es AAAA-MM-DD")
+	}
+}
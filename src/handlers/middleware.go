@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"compilerciclista/src/models"
+	"compilerciclista/src/services"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// requestIDKey guarda en el contexto el identificador de la solicitud.
+type requestIDKey struct{}
+
+// maxRequestIDLength limita los identificadores que envía el cliente.
+const maxRequestIDLength = 64
+
+// WithRequestID asigna a cada solicitud un identificador, que se devuelve en
+// la cabecera X-Request-ID y se anota en la auditoría. Si el cliente (o un
+// proxy) ya envía uno válido, se conserva para poder seguir la solicitud.
+func WithRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Request-ID")
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set("X-Request-ID", id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+	})
+}
+
+// requestID devuelve el identificador asignado por WithRequestID, o "" si la
+// solicitud no pasó por él.
+func requestID(r *http.Request) string {
+	id, _ := r.Context().Value(requestIDKey{}).(string)
+	return id
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// validRequestID acepta letras, dígitos, '-', '_' y '.', para que el valor no
+// pueda inyectar nada en registros ni cabeceras.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// claimsKey guarda en el contexto los claims del token verificado.
+type claimsKey struct{}
+
+// RequireRole protege una ruta: exige un token válido en la cabecera
+// Authorization (Bearer) cuyo rol sea uno de roles, y pone sus claims en el
+// contexto de la solicitud. Sin token, o con uno inválido, responde 401; con
+// un rol no autorizado, 403.
+func RequireRole(roles ...string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			bearer, ok := bearerToken(r)
+			if !ok {
+				unauthorized(w, "Se requiere un token de acceso (Authorization: Bearer).")
+				return
+			}
+			claims, err := services.VerifyToken(bearer)
+			if err != nil {
+				log.Printf("ADVERTENCIA: Token rechazado en %s %s: %v", r.Method, r.URL.Path, err)
+				unauthorized(w, "El token de acceso no es válido o ya venció.")
+				return
+			}
+			if !slices.Contains(roles, claims.Rol) {
+				respondWithError(w, http.StatusForbidden, fmt.Sprintf("El rol '%s' no tiene permiso para esta operación.", claims.Rol))
+				return
+			}
+			next(w, r.WithContext(context.WithValue(r.Context(), claimsKey{}, claims)))
+		}
+	}
+}
+
+// RequireParticipant protege las rutas de autoservicio del corredor dueño
+// del token.
+func RequireParticipant(next http.HandlerFunc) http.HandlerFunc {
+	return RequireRole(models.RolCorredor)(next)
+}
+
+// requestClaims devuelve los claims que verificó RequireRole. En rutas
+// públicas verifica el token si la solicitud lo trae.
+func requestClaims(r *http.Request) (*services.Claims, bool) {
+	if claims, ok := r.Context().Value(claimsKey{}).(*services.Claims); ok {
+		return claims, true
+	}
+	bearer, ok := bearerToken(r)
+	if !ok {
+		return nil, false
+	}
+	claims, err := services.VerifyToken(bearer)
+	return claims, err == nil
+}
+
+// bearerToken extrae el token de la cabecera "Authorization: Bearer <token>".
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	token = strings.TrimSpace(token)
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return token, true
+}
+
+func unauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="compilerciclista"`)
+	respondWithError(w, http.StatusUnauthorized, message)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"compilerciclista/src/models"
+	"compilerciclista/src/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestRequireRole(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "secreto-de-prueba")
+	userToken := func(rol string) string {
+		token, err := services.GenerateUserToken(models.User{Username: rol + "1", Rol: rol, ParticipantID: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	expired, err := jwt.NewWithClaims(jwt.SigningMethodHS256, services.Claims{
+		Rol: models.RolMesa,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "mesa1",
+			Issuer:    "compilerciclista",
+			Audience:  jwt.ClaimStrings{services.StaffAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+			IssuedAt:  jwt.NewNumericDate(time.Now().Add(-time.Hour)),
+		},
+	}).SignedString([]byte("secreto-de-prueba"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var actor string
+	handler := RequireRole(models.DeskRoles...)(func(w http.ResponseWriter, r *http.Request) {
+		actor = requestActor(r)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name          string
+		authorization string
+		status        int
+	}{
+		{"sin token", "", http.StatusUnauthorized},
+		{"otro esquema", "Basic " + userToken(models.RolMesa), http.StatusUnauthorized},
+		{"token vencido", "Bearer " + expired, http.StatusUnauthorized},
+		{"token alterado", "Bearer " + userToken(models.RolMesa) + "x", http.StatusUnauthorized},
+		{"rol sin permiso", "Bearer " + userToken(models.RolJuez), http.StatusForbidden},
+		{"corredor", "Bearer " + userToken(models.RolCorredor), http.StatusForbidden},
+		{"mesa", "Bearer " + userToken(models.RolMesa), http.StatusNoContent},
+		{"esquema en minúsculas", "bearer " + userToken(models.RolAdmin), http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		actor = ""
+		req := httptest.NewRequest("GET", "/participants", nil)
+		if tt.authorization != "" {
+			req.Header.Set("Authorization", tt.authorization)
+		}
+		w := httptest.NewRecorder()
+		handler(w, req)
+		if w.Code != tt.status {
+			t.Errorf("%s: %d %s, se esperaba %d", tt.name, w.Code, w.Body.String(), tt.status)
+		}
+		if w.Code == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("%s: la respuesta 401 no indica el esquema en WWW-Authenticate", tt.name)
+		}
+		if (w.Code == http.StatusNoContent) != (actor != "") {
+			t.Errorf("%s: el handler protegido vio el actor %q", tt.name, actor)
+		}
+	}
+}
@@ -2,21 +2,36 @@ package handlers
 
 import (
 	"compilerciclista/src/database"
+	"compilerciclista/src/diagnostic"
 	"compilerciclista/src/lexer"
 	"compilerciclista/src/models"
 	"compilerciclista/src/parser"
 	"compilerciclista/src/semantic"
 	"compilerciclista/src/services"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"slices"
 	"strings"
+	"time"
 )
 
-// RegisterParticipantHandler procesa la solicitud completa para registrar un nuevo participante.
-func RegisterParticipantHandler(w http.ResponseWriter, r *http.Request) {
+// ParticipantHandler atiende las rutas de registro de participantes sobre el
+// repositorio que se le inyecta (MySQL en producción, memoria en pruebas).
+type ParticipantHandler struct {
+	repo database.ParticipantRepository
+}
+
+// NewParticipantHandler crea los handlers de participantes sobre repo.
+func NewParticipantHandler(repo database.ParticipantRepository) *ParticipantHandler {
+	return &ParticipantHandler{repo: repo}
+}
+
+// Register procesa la solicitud completa para registrar un nuevo participante.
+func (h *ParticipantHandler) Register(w http.ResponseWriter, r *http.Request) {
 	// Leer y procesar el DSL de entrada
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -31,13 +46,19 @@ func RegisterParticipantHandler(w http.ResponseWriter, r *http.Request) {
 	participantData, parsingErrors := p.ParseProgram()
 
 	if len(parsingErrors) > 0 {
-		respondWithError(w, http.StatusBadRequest, parsingErrors)
+		respondWithDiagnostics(w, r, http.StatusBadRequest, parsingErrors)
 		return
 	}
 
-	semanticErrors := semantic.Analyze(participantData)
-	if len(semanticErrors) > 0 {
-		respondWithError(w, http.StatusBadRequest, semanticErrors)
+	// Solo el personal de registro puede dar por hecho un pago
+	paymentIgnored := false
+	if !canRecordPayments(r) {
+		paymentIgnored = stripPaymentFields(participantData)
+	}
+
+	semanticErrors := semantic.Analyze(participantData, p.Spans())
+	if diagnostic.HasErrors(semanticErrors) {
+		respondWithDiagnostics(w, r, http.StatusBadRequest, semanticErrors)
 		return
 	}
 
@@ -48,12 +69,100 @@ func RegisterParticipantHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Generar el CÓDIGO DE PARTICIPANTE único
-	participantCode, err := services.GenerateParticipantCode(participantModel.Categoria)
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "No se pudo generar el código de participante.")
+	// Generar el código, subir archivos y guardar en la base de datos
+	participantModel, regErr := h.register(participantModel, originOf(r))
+	if regErr != nil {
+		respondWithError(w, regErr.status, regErr.message)
 		return
 	}
+
+	// Preparar la respuesta JSON final
+	responsePayload := map[string]interface{}{
+		"message":     "Participante registrado exitosamente.",
+		"participant": participantModel, // El modelo completo con ID y Código de Participante
+	}
+	if len(semanticErrors) > 0 {
+		responsePayload["warnings"] = localize(r, semanticErrors) // Solo quedan advertencias
+	}
+	if paymentIgnored {
+		responsePayload["payment_notice"] = "El pago lo registra la organización: se ignoraron 'pago_realizado' y 'comprobante_pago_path'. " +
+			"El comprobante se puede subir con POST /me/documents."
+	}
+	notifyParticipant(participantModel, responsePayload)
+
+	// Enviar la respuesta final completa al cliente
+	respondWithJSON(w, http.StatusCreated, responsePayload)
+}
+
+// paymentFields son los campos con los que se da por hecho un pago.
+var paymentFields = []string{"pago_realizado", "comprobante_pago_path"}
+
+// canRecordPayments indica si el token de la solicitud es de alguien que
+// puede registrar pagos (ver models.DeskRoles).
+func canRecordPayments(r *http.Request) bool {
+	claims, ok := requestClaims(r)
+	return ok && slices.Contains(models.DeskRoles, claims.Rol)
+}
+
+// stripPaymentFields quita de data los campos de pago e indica si alguno
+// declaraba un pago o un comprobante.
+func stripPaymentFields(data parser.ParticipantData) bool {
+	declared := false
+	for _, field := range paymentFields {
+		switch value := data[field].(type) {
+		case bool:
+			declared = declared || value
+		case string:
+			declared = declared || value != ""
+		}
+		delete(data, field)
+	}
+	return declared
+}
+
+// registrationError es un fallo al guardar un participante, junto con el
+// código HTTP con el que se debe responder.
+type registrationError struct {
+	status  int
+	message string
+}
+
+func (e *registrationError) Error() string {
+	return e.message
+}
+
+// register guarda un participante en su propia transacción, de modo que el
+// número de su código solo se consume si la inserción se confirma.
+func (h *ParticipantHandler) register(participantModel models.Participant, origin auditOrigin) (models.Participant, *registrationError) {
+	var stored models.Participant
+	var regErr *registrationError
+	err := h.repo.WithTx(func(tx database.ParticipantRepository) error {
+		stored, regErr = storeParticipant(tx, participantModel, origin)
+		if regErr != nil {
+			return regErr
+		}
+		return nil
+	})
+	if regErr != nil {
+		return participantModel, regErr
+	}
+	if err != nil {
+		return participantModel, &registrationError{http.StatusInternalServerError, "No se pudo confirmar el registro del participante"}
+	}
+	return stored, nil
+}
+
+// storeParticipant genera el código de participante, sube los archivos y
+// guarda el registro en repo (normalmente, una transacción abierta), junto
+// con su entrada en la auditoría.
+func storeParticipant(repo database.ParticipantRepository, participantModel models.Participant, origin auditOrigin) (models.Participant, *registrationError) {
+	// Generar el CÓDIGO DE PARTICIPANTE único dentro del evento
+	participantModel.Evento = semantic.ActiveSchema().Event
+	participantModel.Estado = models.EstadoActivo
+	participantCode, err := services.GenerateParticipantCode(repo, participantModel.Evento, participantModel.Categoria)
+	if err != nil {
+		return participantModel, &registrationError{http.StatusInternalServerError, "No se pudo generar el código de participante."}
+	}
 	// Asignamos el código generado a nuestro modelo antes de guardarlo.
 	participantModel.ParticipantCode = participantCode
 
@@ -69,44 +178,59 @@ func RegisterParticipantHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Guardar el participante en la Base de Datos
 	// El modelo ahora contiene el código de participante generado.
-	id, err := database.CreateParticipant(participantModel)
+	id, err := repo.Create(participantModel)
 	if err != nil {
-		if strings.Contains(err.Error(), "Duplicate entry") {
-			// Este error ahora puede ser por un email o un código de participante duplicado
-			respondWithError(w, http.StatusConflict, fmt.Sprintf("Conflicto de datos: El email '%s' o el código generado ya existe.", participantModel.Email))
-			return
+		var duplicate *database.DuplicateError
+		if errors.As(err, &duplicate) {
+			return participantModel, &registrationError{http.StatusConflict, duplicateMessage(duplicate, participantModel)}
 		}
-		respondWithError(w, http.StatusInternalServerError, "Error al guardar el participante en la base de datos")
-		return
+		return participantModel, &registrationError{http.StatusInternalServerError, "Error al guardar el participante en la base de datos"}
 	}
 	participantModel.ID = id // Asignamos el ID autoincremental de la DB
 
-	// Preparar la respuesta JSON final
-	responsePayload := map[string]interface{}{
-		"message":     "Participante registrado exitosamente.",
-		"participant": participantModel, // El modelo completo con ID y Código de Participante
+	if err := origin.record(repo, models.AccionRegistro, nil, participantModel); err != nil {
+		log.Printf("ERROR: No se pudo auditar el registro del participante %d: %v", id, err)
+		return participantModel, &registrationError{http.StatusInternalServerError, "Error al guardar el participante en la base de datos"}
+	}
+
+	return participantModel, nil
+}
+
+// duplicateMessage explica con qué dato choca el participante.
+func duplicateMessage(duplicate *database.DuplicateError, p models.Participant) string {
+	switch duplicate.Field {
+	case "curp":
+		// La CURP identifica a la persona aunque se registre con otro email
+		return fmt.Sprintf("Conflicto de datos: Ya hay un participante registrado en este evento con la CURP '%s'.", p.CURP)
+	case "email":
+		return fmt.Sprintf("Conflicto de datos: El email '%s' ya está registrado en este evento.", p.Email)
+	default:
+		return fmt.Sprintf("Conflicto de datos: El código generado '%s' ya existe.", p.ParticipantCode)
 	}
+}
 
+// notifyParticipant genera el token JWT y envía el correo de confirmación de un
+// participante ya guardado, anotando en payload el resultado de cada paso.
+func notifyParticipant(participantModel models.Participant, payload map[string]interface{}) {
 	// Generar el TOKEN JWT (para la seguridad de la API)
 	jwtToken, err := services.GenerateToken(participantModel)
 	if err != nil {
 		log.Printf("ADVERTENCIA: No se pudo generar el token JWT: %v", err)
-		responsePayload["token_warning"] = "No se pudo generar el token de acceso JWT."
+		payload["token_warning"] = "No se pudo generar el token de acceso JWT."
 	} else {
-		responsePayload["access_token"] = jwtToken
+		payload["access_token"] = jwtToken
 	}
 
-	// 9. Enviar el CORREO DE CONFIRMACIÓN (pasando el código de participante)
+	// Enviar el CORREO DE CONFIRMACIÓN (pasando el código de participante)
 	if err_email := services.SendConfirmationEmail(participantModel.Email, participantModel.Nombre, participantModel.ParticipantCode); err_email != nil {
-		log.Printf("ADVERTENCIA: El correo para el participante %d no se pudo enviar: %v", id, err_email)
-		responsePayload["email_warning"] = "El servicio de correo falló: " + err_email.Error()
+		log.Printf("ADVERTENCIA: El correo para el participante %d no se pudo enviar: %v", participantModel.ID, err_email)
+		payload["email_warning"] = "El servicio de correo falló: " + err_email.Error()
 	}
-
-	// Enviar la respuesta final completa al cliente
-	respondWithJSON(w, http.StatusCreated, responsePayload)
 }
 
 // populateModel convierte el mapa de datos del parser a un struct de Participant.
+// El esquema garantiza los campos obligatorios (ver checkModelFields en
+// src/schema), así que un error aquí indica un documento que no se validó.
 func populateModel(data parser.ParticipantData) (models.Participant, error) {
 	var p models.Participant
 	var ok bool
@@ -132,10 +256,44 @@ func populateModel(data parser.ParticipantData) (models.Participant, error) {
 	p.PagoRealizado, _ = data["pago_realizado"].(bool)
 	p.InePath, _ = data["ine_path"].(string)
 	p.ComprobantePagoPath, _ = data["comprobante_pago_path"].(string)
+	if curp, ok := data["curp"].(string); ok {
+		p.CURP = strings.ToUpper(strings.TrimSpace(curp))
+	}
+	if birth, ok := data["fecha_nacimiento"].(time.Time); ok {
+		p.FechaNacimiento = birth.Format(parser.DateLayout)
+	}
 
 	return p, nil
 }
 
+// documentErrorMessages es el resumen que acompaña a los diagnósticos, por idioma.
+var documentErrorMessages = map[diagnostic.Lang]string{
+	diagnostic.Spanish: "El documento contiene errores.",
+	diagnostic.English: "The document contains errors.",
+}
+
+// requestLang elige el idioma de los mensajes según la cabecera
+// Accept-Language de la solicitud; por defecto, español.
+func requestLang(r *http.Request) diagnostic.Lang {
+	return diagnostic.NegotiateLang(r.Header.Get("Accept-Language"))
+}
+
+// localize traduce los diagnósticos al idioma pedido por el cliente.
+func localize(r *http.Request, diagnostics []diagnostic.Diagnostic) []diagnostic.Diagnostic {
+	return diagnostic.LocalizeAll(diagnostics, requestLang(r))
+}
+
+// respondWithDiagnostics responde con los diagnósticos del documento, cada uno
+// con su código, severidad, campo y ubicación, para que el cliente marque los
+// campos del formulario que tienen problemas.
+func respondWithDiagnostics(w http.ResponseWriter, r *http.Request, code int, diagnostics []diagnostic.Diagnostic) {
+	lang := requestLang(r)
+	respondWithJSON(w, code, map[string]interface{}{
+		"error":       documentErrorMessages[lang],
+		"diagnostics": diagnostic.LocalizeAll(diagnostics, lang),
+	})
+}
+
 // respondWithError es una función helper para enviar respuestas de error en JSON.
 func respondWithError(w http.ResponseWriter, code int, message interface{}) {
 	respondWithJSON(w, code, map[string]interface{}{"error": message})
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"compilerciclista/src/database"
+	"compilerciclista/src/models"
+	"compilerciclista/src/parser"
+	"compilerciclista/src/semantic"
+	"compilerciclista/src/services"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testServer monta las rutas de participantes sobre un repositorio en
+// memoria. Las rutas no llevan los guardias de rol de main.go; los handlers
+// leen el token de la solicitud si lo trae.
+type testServer struct {
+	t    *testing.T
+	repo *database.MemoryRepository
+	mux  *http.ServeMux
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Setenv("JWT_SECRET_KEY", "secreto-de-prueba")
+	t.Setenv("SMTP_HOST", "") // sin correo de confirmación
+
+	repo := database.NewMemoryRepository()
+	participants := NewParticipantHandler(repo)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/register", participants.Register)
+	mux.HandleFunc("/register/batch", participants.RegisterBatch)
+	mux.HandleFunc("PATCH /participants/{code}", participants.Update)
+	mux.HandleFunc("POST /participants/{code}/withdraw", participants.Withdraw)
+	mux.HandleFunc("POST /participants/{code}/reinstate", participants.Reinstate)
+	return &testServer{t: t, repo: repo, mux: mux}
+}
+
+// do envía la solicitud (con el token, si no es vacío) y decodifica la respuesta.
+func (s *testServer) do(method, path, token, body string) (int, map[string]interface{}) {
+	s.t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	s.mux.ServeHTTP(w, req)
+	var payload map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &payload); err != nil {
+		s.t.Fatalf("%s %s: respuesta que no es JSON: %s", method, path, w.Body.String())
+	}
+	return w.Code, payload
+}
+
+// token devuelve un token del personal con el rol indicado.
+func (s *testServer) token(rol string) string {
+	token, err := services.GenerateUserToken(models.User{Username: rol + "1", Rol: rol})
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	return token
+}
+
+// register registra un documento y falla la prueba si no se acepta.
+func (s *testServer) register(body string) {
+	s.t.Helper()
+	if status, payload := s.do("POST", "/register", "", body); status != http.StatusCreated {
+		s.t.Fatalf("registro: %d %v", status, payload)
+	}
+}
+
+// find devuelve al participante guardado con el código indicado.
+func (s *testServer) find(code string) models.Participant {
+	s.t.Helper()
+	p, err := s.repo.FindByCode(semantic.ActiveSchema().Event, code)
+	if err != nil {
+		s.t.Fatalf("FindByCode(%s): %v", code, err)
+	}
+	return p
+}
+
+// registration es un documento válido en la categoría Elite.
+func registration(email string) string {
+	birth := time.Now().AddDate(-25, 0, 0).Format(parser.DateLayout)
+	return fmt.Sprintf(`nombre: "Ana"; apellido_paterno: "López"; email: "%s"; sexo: "F"; fecha_nacimiento: %s;`, email, birth)
+}
+
+func TestRegister(t *testing.T) {
+	s := newTestServer(t)
+
+	status, payload := s.do("POST", "/register", "", registration("ana@unam.mx"))
+	if status != http.StatusCreated {
+		t.Fatalf("registro: %d %v", status, payload)
+	}
+	if _, ok := payload["access_token"]; !ok {
+		t.Errorf("el registro no devolvió el token: %v", payload)
+	}
+	p := s.find("ELI-001")
+	if p.Categoria != "Elite" || p.Nombre != "Ana" || p.ID == 0 {
+		t.Errorf("participante guardado: %+v", p)
+	}
+
+	s.register(registration("luis@unam.mx"))
+	if p := s.find("ELI-002"); p.Email != "luis@unam.mx" {
+		t.Errorf("el segundo registro de la categoría es %+v", p)
+	}
+
+	status, _ = s.do("POST", "/register", "", registration("ana@unam.mx"))
+	if status != http.StatusConflict {
+		t.Errorf("un email repetido respondió %d, se esperaba 409", status)
+	}
+
+	status, payload = s.do("POST", "/register", "", `nombre: "Ana"; sexo: "X";`)
+	if status != http.StatusBadRequest || payload["diagnostics"] == nil {
+		t.Errorf("un documento inválido respondió %d %v, se esperaban diagnósticos", status, payload)
+	}
+	status, _ = s.do("POST", "/register", "", `nombre "Ana"`)
+	if status != http.StatusBadRequest {
+		t.Errorf("un documento mal escrito respondió %d, se esperaba 400", status)
+	}
+
+	participants, total, err := s.repo.List(database.ParticipantFilter{Evento: semantic.ActiveSchema().Event, Limit: 10})
+	if err != nil || total != 2 || len(participants) != 2 {
+		t.Errorf("List = %d participantes de %d, %v; se esperaban 2", len(participants), total, err)
+	}
+}
+
+func TestRegisterPaymentRequiresDesk(t *testing.T) {
+	s := newTestServer(t)
+	paid := ` pago_realizado: true; comprobante_pago_path: "pago.pdf";`
+
+	status, payload := s.do("POST", "/register", "", registration("ana@unam.mx")+paid)
+	if status != http.StatusCreated || payload["payment_notice"] == nil {
+		t.Fatalf("registro anónimo: %d %v", status, payload)
+	}
+	if p := s.find("ELI-001"); p.PagoRealizado || p.ComprobantePagoPath != "" {
+		t.Errorf("el registro anónimo guardó el pago: %+v", p)
+	}
+
+	status, payload = s.do("POST", "/register", s.token(models.RolJuez), registration("eva@unam.mx")+paid)
+	if status != http.StatusCreated || payload["payment_notice"] == nil {
+		t.Fatalf("registro con token de juez: %d %v", status, payload)
+	}
+
+	status, payload = s.do("POST", "/register", s.token(models.RolMesa), registration("luis@unam.mx")+paid)
+	if status != http.StatusCreated || payload["payment_notice"] != nil {
+		t.Fatalf("registro en mesa: %d %v", status, payload)
+	}
+	p := s.find("ELI-003")
+	if !p.PagoRealizado || p.ComprobantePagoPath == "" {
+		t.Errorf("el registro en mesa no guardó el pago: %+v", p)
+	}
+	history, err := s.repo.History(p.ID)
+	if err != nil || len(history) != 1 || history[0].Actor != "mesa_registro1" {
+		t.Errorf("auditoría del registro en mesa: %+v, %v", history, err)
+	}
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"compilerciclista/src/database"
+	"compilerciclista/src/models"
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// documentFields son los campos que un participante puede actualizar por su
+// cuenta con UploadMyDocuments.
+var documentFields = map[string]bool{
+	"ine_path":              true,
+	"comprobante_pago_path": true,
+}
+
+// Me responde con la inscripción del participante dueño del token, en
+// cualquier estado. Requiere RequireParticipant.
+func (h *ParticipantHandler) Me(w http.ResponseWriter, r *http.Request) {
+	claims, _ := requestClaims(r)
+	participant, err := h.repo.FindByID(claims.ParticipantID)
+	if errors.Is(err, database.ErrNotFound) {
+		respondWithError(w, http.StatusNotFound, "La inscripción de este token ya no existe.")
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: No se pudo buscar al participante %d: %v", claims.ParticipantID, err)
+		respondWithError(w, http.StatusInternalServerError, "No se pudo obtener la inscripción")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, participant)
+}
+
+// UploadMyDocuments permite al participante dueño del token adjuntar o
+// reemplazar su INE y su comprobante de pago. El cuerpo es un documento DSL
+// con solo esos campos; marcar el pago y corregir otros datos queda a cargo
+// de la organización. Requiere RequireParticipant.
+func (h *ParticipantHandler) UploadMyDocuments(w http.ResponseWriter, r *http.Request) {
+	patch, spans, ok := readPatch(w, r)
+	if !ok {
+		return
+	}
+	var forbidden []string
+	for key := range patch {
+		if !documentFields[key] {
+			forbidden = append(forbidden, "'"+key+"'")
+		}
+	}
+	if len(forbidden) > 0 {
+		sort.Strings(forbidden)
+		respondWithError(w, http.StatusForbidden, "Solo se pueden subir 'ine_path' y 'comprobante_pago_path'; no se permite modificar "+strings.Join(forbidden, ", ")+".")
+		return
+	}
+
+	claims, _ := requestClaims(r)
+	h.correct(w, r, claims.ParticipantCode, models.AccionDocumentos, patch, spans, func(tx database.ParticipantRepository) (models.Participant, error) {
+		return tx.FindByIDForUpdate(claims.ParticipantID)
+	})
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"compilerciclista/src/database"
+	"compilerciclista/src/models"
+	"compilerciclista/src/semantic"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// Withdraw da de baja una inscripción sin borrarla. El cuerpo, opcional, es
+// un JSON {"estado": "..."} con el motivo de la baja: retirado (por defecto),
+// cancelado o descalificado. El participante conserva su código.
+func (h *ParticipantHandler) Withdraw(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Estado string `json:"estado"`
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "No se pudo leer el cuerpo de la solicitud")
+		return
+	}
+	if len(strings.TrimSpace(string(body))) > 0 {
+		if err := json.Unmarshal(body, &request); err != nil {
+			respondWithError(w, http.StatusBadRequest, "El cuerpo debe ser un JSON como {\"estado\": \"retirado\"}")
+			return
+		}
+	}
+	if request.Estado == "" {
+		request.Estado = models.EstadoRetirado
+	}
+	if request.Estado == models.EstadoActivo || !models.IsEstado(request.Estado) {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("El estado de baja debe ser uno de: %s", strings.Join(models.Estados[1:], ", ")))
+		return
+	}
+	h.changeStatus(w, r, request.Estado)
+}
+
+// Reinstate reactiva una inscripción dada de baja, con el mismo código.
+func (h *ParticipantHandler) Reinstate(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, models.EstadoActivo)
+}
+
+// changeStatus pasa al participante de la ruta al estado indicado y anota el
+// cambio en la auditoría. Las bajas solo se aplican a inscripciones activas y
+// la reactivación solo a las que no lo están.
+func (h *ParticipantHandler) changeStatus(w http.ResponseWriter, r *http.Request, estado string) {
+	code := r.PathValue("code")
+	var participant models.Participant
+	var statusErr *registrationError
+	err := h.repo.WithTx(func(tx database.ParticipantRepository) error {
+		var err error
+		participant, err = tx.FindByCodeForUpdate(semantic.ActiveSchema().Event, code)
+		if errors.Is(err, database.ErrNotFound) {
+			statusErr = &registrationError{http.StatusNotFound, fmt.Sprintf("No existe un participante con el código '%s'.", code)}
+			return statusErr
+		}
+		if err != nil {
+			return err
+		}
+
+		previous := participant.Estado
+		switch {
+		case estado == models.EstadoActivo && previous == models.EstadoActivo:
+			statusErr = &registrationError{http.StatusConflict, fmt.Sprintf("La inscripción '%s' ya está activa.", code)}
+		case estado != models.EstadoActivo && previous != models.EstadoActivo:
+			statusErr = &registrationError{http.StatusConflict, fmt.Sprintf("La inscripción '%s' ya está en estado '%s'.", code, previous)}
+		}
+		if statusErr != nil {
+			return statusErr
+		}
+
+		before := participant
+		participant.Estado = estado
+		if err := tx.Update(participant); err != nil {
+			return err
+		}
+		action := models.AccionBaja
+		if estado == models.EstadoActivo {
+			action = models.AccionReactivacion
+		}
+		return originOf(r).record(tx, action, &before, participant)
+	})
+
+	switch {
+	case statusErr != nil:
+		respondWithError(w, statusErr.status, statusErr.message)
+		return
+	case err != nil:
+		log.Printf("ERROR: No se pudo cambiar el estado del participante %s: %v", code, err)
+		respondWithError(w, http.StatusInternalServerError, "No se pudo cambiar el estado de la inscripción")
+		return
+	}
+
+	message := fmt.Sprintf("Inscripción '%s' dada de baja (%s).", code, estado)
+	if estado == models.EstadoActivo {
+		message = fmt.Sprintf("Inscripción '%s' reactivada.", code)
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message":     message,
+		"participant": participant,
+	})
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"compilerciclista/src/database"
+	"compilerciclista/src/models"
+	"compilerciclista/src/semantic"
+	"net/http"
+	"testing"
+)
+
+func TestWithdrawAndReinstate(t *testing.T) {
+	s := newTestServer(t)
+	s.register(registration("ana@unam.mx"))
+
+	steps := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		estado string // estado guardado después del paso
+	}{
+		{"estado desconocido", "POST", "/participants/ELI-001/withdraw", `{"estado": "perdido"}`, http.StatusBadRequest, models.EstadoActivo},
+		{"baja como activo", "POST", "/participants/ELI-001/withdraw", `{"estado": "activo"}`, http.StatusBadRequest, models.EstadoActivo},
+		{"cuerpo que no es JSON", "POST", "/participants/ELI-001/withdraw", `retirado`, http.StatusBadRequest, models.EstadoActivo},
+		{"baja", "POST", "/participants/ELI-001/withdraw", `{"estado": "descalificado"}`, http.StatusOK, models.EstadoDescalificado},
+		{"baja repetida", "POST", "/participants/ELI-001/withdraw", "", http.StatusConflict, models.EstadoDescalificado},
+		{"corrección de una baja", "PATCH", "/participants/ELI-001", `nombre: "Eva";`, http.StatusConflict, models.EstadoDescalificado},
+		{"reactivación", "POST", "/participants/ELI-001/reinstate", "", http.StatusOK, models.EstadoActivo},
+		{"reactivación repetida", "POST", "/participants/ELI-001/reinstate", "", http.StatusConflict, models.EstadoActivo},
+		{"baja por defecto", "POST", "/participants/ELI-001/withdraw", "", http.StatusOK, models.EstadoRetirado},
+		{"código inexistente", "POST", "/participants/ELI-999/withdraw", "", http.StatusNotFound, models.EstadoRetirado},
+	}
+	for _, step := range steps {
+		if status, payload := s.do(step.method, step.path, "", step.body); status != step.status {
+			t.Fatalf("%s: %d %v, se esperaba %d", step.name, status, payload, step.status)
+		}
+		if p := s.find("ELI-001"); p.Estado != step.estado || p.ParticipantCode != "ELI-001" {
+			t.Fatalf("%s: participante %+v, se esperaba el estado %s", step.name, p, step.estado)
+		}
+	}
+}
+
+// TestWithdrawnCodeIsNotReissued comprueba que una baja no libera su código
+// y que las bajas no aparecen en la lista.
+func TestWithdrawnCodeIsNotReissued(t *testing.T) {
+	s := newTestServer(t)
+	s.register(registration("ana@unam.mx"))
+	if status, payload := s.do("POST", "/participants/ELI-001/withdraw", "", ""); status != http.StatusOK {
+		t.Fatalf("baja: %d %v", status, payload)
+	}
+	s.register(registration("luis@unam.mx"))
+	if p := s.find("ELI-002"); p.Email != "luis@unam.mx" {
+		t.Errorf("ELI-002 es %+v", p)
+	}
+
+	filter := database.ParticipantFilter{Evento: semantic.ActiveSchema().Event, Limit: 10}
+	participants, total, err := s.repo.List(filter)
+	if err != nil || total != 1 || participants[0].ParticipantCode != "ELI-002" {
+		t.Errorf("List = %+v (total %d), %v; se esperaba solo ELI-002", participants, total, err)
+	}
+	filter.Estado = models.EstadoRetirado
+	if _, total, err := s.repo.List(filter); err != nil || total != 1 {
+		t.Errorf("List de retirados: total %d, %v; se esperaba 1", total, err)
+	}
+}
@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"compilerciclista/src/database"
+	"compilerciclista/src/diagnostic"
+	"compilerciclista/src/lexer"
+	"compilerciclista/src/models"
+	"compilerciclista/src/parser"
+	"compilerciclista/src/schema"
+	"compilerciclista/src/semantic"
+	"compilerciclista/src/services"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Update corrige los datos de un participante. El cuerpo es un documento DSL
+// parcial con solo los campos que cambian; el registro combinado se vuelve a
+// validar completo y, si cambia la categoría, recibe un código nuevo. La
+// corrección queda en la auditoría con el participante antes y después.
+func (h *ParticipantHandler) Update(w http.ResponseWriter, r *http.Request) {
+	patch, spans, ok := readPatch(w, r)
+	if !ok {
+		return
+	}
+	code := r.PathValue("code")
+	h.correct(w, r, code, "", patch, spans, func(tx database.ParticipantRepository) (models.Participant, error) {
+		return tx.FindByCodeForUpdate(semantic.ActiveSchema().Event, code)
+	})
+}
+
+// participantLookup busca, dentro de la transacción tx, al participante que
+// se va a corregir. Debe bloquear su fila (FindByCodeForUpdate o
+// FindByIDForUpdate) para que dos correcciones simultáneas no se pisen.
+type participantLookup func(tx database.ParticipantRepository) (models.Participant, error)
+
+// readPatch lee el documento DSL parcial del cuerpo. Si no es válido responde
+// con el error y devuelve ok en false.
+func readPatch(w http.ResponseWriter, r *http.Request) (patch parser.ParticipantData, spans parser.Spans, ok bool) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "No se pudo leer el cuerpo de la solicitud")
+		return nil, nil, false
+	}
+
+	p := parser.New(lexer.New(string(body)))
+	patch, parsingErrors := p.ParseProgram()
+	if len(parsingErrors) > 0 {
+		respondWithDiagnostics(w, r, http.StatusBadRequest, parsingErrors)
+		return nil, nil, false
+	}
+	if len(patch) == 0 {
+		respondWithError(w, http.StatusBadRequest, "El documento no contiene campos para modificar.")
+		return nil, nil, false
+	}
+	return patch, p.Spans(), true
+}
+
+// correct aplica patch al participante que devuelve lookup (identificado por
+// code en los mensajes) y responde con el resultado. action es la acción que
+// se anota en la auditoría; vacía, se deduce de los campos que cambian.
+func (h *ParticipantHandler) correct(w http.ResponseWriter, r *http.Request, code, action string, patch parser.ParticipantData, spans parser.Spans, lookup participantLookup) {
+	origin := originOf(r)
+	var before, after models.Participant
+	var semanticErrors []diagnostic.Diagnostic
+	var changes []models.Change
+	var updErr *registrationError
+	err := h.repo.WithTx(func(tx database.ParticipantRepository) error {
+		var err error
+		before, err = lookup(tx)
+		if errors.Is(err, database.ErrNotFound) {
+			updErr = &registrationError{http.StatusNotFound, fmt.Sprintf("No existe un participante con el código '%s'.", code)}
+			return updErr
+		}
+		if err != nil {
+			return err
+		}
+		if before.Estado != models.EstadoActivo {
+			updErr = &registrationError{http.StatusConflict, fmt.Sprintf("La inscripción '%s' está en estado '%s'; reactívela antes de corregirla.", code, before.Estado)}
+			return updErr
+		}
+
+		merged := mergePatch(before, patch)
+		semanticErrors = semantic.AnalyzeWith(schemaAsOf(before), merged, spans)
+		if diagnostic.HasErrors(semanticErrors) {
+			return errInvalidPatch
+		}
+
+		after, updErr = applyPatch(tx, before, merged)
+		if updErr != nil {
+			return updErr
+		}
+		changes = diffParticipants(before, after)
+		if len(changes) == 0 {
+			return nil
+		}
+		if err := tx.Update(after); err != nil {
+			var duplicate *database.DuplicateError
+			if errors.As(err, &duplicate) {
+				updErr = &registrationError{http.StatusConflict, duplicateMessage(duplicate, after)}
+				return updErr
+			}
+			return err
+		}
+		if action == "" {
+			action = correctionAction(changes)
+		}
+		return origin.record(tx, action, &before, after)
+	})
+
+	switch {
+	case errors.Is(err, errInvalidPatch):
+		respondWithDiagnostics(w, r, http.StatusBadRequest, semanticErrors)
+		return
+	case updErr != nil:
+		respondWithError(w, updErr.status, updErr.message)
+		return
+	case err != nil:
+		log.Printf("ERROR: No se pudo actualizar al participante %s: %v", code, err)
+		respondWithError(w, http.StatusInternalServerError, "No se pudo actualizar el participante")
+		return
+	}
+
+	responsePayload := map[string]interface{}{
+		"message":     "Participante actualizado exitosamente.",
+		"participant": after,
+		"changes":     changes,
+	}
+	if len(changes) == 0 {
+		responsePayload["message"] = "No hubo cambios: los datos ya eran los mismos."
+		responsePayload["changes"] = []models.Change{}
+	}
+	if len(semanticErrors) > 0 {
+		responsePayload["warnings"] = localize(r, semanticErrors)
+	}
+	respondWithJSON(w, http.StatusOK, responsePayload)
+}
+
+// schemaAsOf devuelve el esquema activo para revalidar una inscripción. Si el
+// evento no fija la fecha de la carrera, la edad se calculó al registrarse, así
+// que se toma esa fecha en lugar de la de hoy: un cumpleaños posterior no
+// cambia la categoría de quien ya está inscrito.
+func schemaAsOf(p models.Participant) *schema.Schema {
+	active := semantic.ActiveSchema()
+	if !active.RaceDate.IsZero() || p.CreatedAt == nil {
+		return active
+	}
+	s := *active
+	s.RaceDate = *p.CreatedAt
+	return &s
+}
+
+// errInvalidPatch cancela la transacción cuando el registro combinado no es válido.
+var errInvalidPatch = errors.New("el registro corregido no es válido")
+
+// mergePatch combina los datos guardados del participante con los campos del
+// documento parcial. Si cambia la fecha de nacimiento o el sexo sin fijar la
+// categoría y el esquema la asigna por edad, se descarta la guardada para
+// recalcularla, salvo que sea una categoría abierta.
+func mergePatch(p models.Participant, patch parser.ParticipantData) parser.ParticipantData {
+	merged := dataFromModel(p)
+	_, birth := patch["fecha_nacimiento"]
+	_, sexo := patch["sexo"]
+	_, categoria := patch["categoria"]
+	active := semantic.ActiveSchema()
+	if (birth || sexo) && !categoria && active.AutoCategory && !active.Categories.IsOpen(p.Categoria) {
+		delete(merged, "categoria")
+	}
+	for key, value := range patch {
+		merged[key] = value
+	}
+	return merged
+}
+
+// dataFromModel convierte un participante guardado al mapa que produce el
+// parser, para validarlo igual que un documento nuevo.
+func dataFromModel(p models.Participant) parser.ParticipantData {
+	data := parser.ParticipantData{
+		"nombre":           p.Nombre,
+		"apellido_paterno": p.ApellidoPaterno,
+		"email":            p.Email,
+		"sexo":             p.Sexo,
+		"categoria":        p.Categoria,
+		"pago_realizado":   p.PagoRealizado,
+	}
+	optional := map[string]string{
+		"apellido_materno":      p.ApellidoMaterno,
+		"curp":                  p.CURP,
+		"ine_path":              p.InePath,
+		"comprobante_pago_path": p.ComprobantePagoPath,
+	}
+	for key, value := range optional {
+		if value != "" {
+			data[key] = value
+		}
+	}
+	if birth, err := time.Parse(parser.DateLayout, p.FechaNacimiento); err == nil {
+		data["fecha_nacimiento"] = birth
+	}
+	return data
+}
+
+// applyPatch construye el participante corregido: conserva su identidad,
+// sube los archivos nuevos y le da un código nuevo si cambió de categoría.
+func applyPatch(tx database.ParticipantRepository, before models.Participant, merged parser.ParticipantData) (models.Participant, *registrationError) {
+	after, err := populateModel(merged)
+	if err != nil {
+		return before, &registrationError{http.StatusInternalServerError, err.Error()}
+	}
+	after.ID = before.ID
+	after.Evento = before.Evento
+	after.ParticipantCode = before.ParticipantCode
+	after.Estado = before.Estado
+	after.CreatedAt = before.CreatedAt
+
+	// (Simulado) Subir solo los archivos que cambiaron
+	if after.InePath != before.InePath && after.InePath != "" {
+		after.InePath, _ = services.UploadFile(after.InePath)
+	}
+	if after.ComprobantePagoPath != before.ComprobantePagoPath && after.ComprobantePagoPath != "" {
+		after.ComprobantePagoPath, _ = services.UploadFile(after.ComprobantePagoPath)
+	}
+
+	// El código anterior no se reutiliza: la secuencia nunca retrocede
+	if after.Categoria != before.Categoria {
+		code, err := services.GenerateParticipantCode(tx, after.Evento, after.Categoria)
+		if err != nil {
+			return before, &registrationError{http.StatusInternalServerError, "No se pudo generar el código de participante."}
+		}
+		after.ParticipantCode = code
+	}
+	return after, nil
+}
+
+// diffParticipants lista los campos que cambiaron, nombrados como en el DSL.
+func diffParticipants(before, after models.Participant) []models.Change {
+	var changes []models.Change
+	old, updated := reflect.ValueOf(before), reflect.ValueOf(after)
+	for i := 0; i < old.NumField(); i++ {
+		name := strings.Split(old.Type().Field(i).Tag.Get("json"), ",")[0]
+		if name == "id" || name == "evento" || name == "created_at" {
+			continue
+		}
+		oldValue := fmt.Sprint(old.Field(i).Interface())
+		newValue := fmt.Sprint(updated.Field(i).Interface())
+		if oldValue != newValue {
+			changes = append(changes, models.Change{Field: name, Before: oldValue, After: newValue})
+		}
+	}
+	return changes
+}
+
+// correctionAction clasifica una corrección para la auditoría: es un pago si
+// solo cambian el pago y su comprobante.
+func correctionAction(changes []models.Change) string {
+	for _, c := range changes {
+		if c.Field != "pago_realizado" && c.Field != "comprobante_pago_path" {
+			return models.AccionCorreccion
+		}
+	}
+	return models.AccionPago
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"compilerciclista/src/models"
+	"compilerciclista/src/semantic"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUpdate(t *testing.T) {
+	s := newTestServer(t)
+	s.register(registration("ana@unam.mx"))
+	s.register(registration("luis@unam.mx"))
+
+	tests := []struct {
+		name   string
+		code   string
+		body   string
+		status int
+	}{
+		{"código inexistente", "ELI-999", `nombre: "Eva";`, http.StatusNotFound},
+		{"valor inválido", "ELI-001", `sexo: "X";`, http.StatusBadRequest},
+		{"documento mal escrito", "ELI-001", `nombre "Eva"`, http.StatusBadRequest},
+		{"documento vacío", "ELI-001", ``, http.StatusBadRequest},
+		{"email de otro participante", "ELI-001", `email: "luis@unam.mx";`, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		if status, payload := s.do("PATCH", "/participants/"+tt.code, "", tt.body); status != tt.status {
+			t.Errorf("%s: %d %v, se esperaba %d", tt.name, status, payload, tt.status)
+		}
+	}
+	if p := s.find("ELI-001"); p.Nombre != "Ana" || p.Sexo != "F" || p.Email != "ana@unam.mx" {
+		t.Fatalf("una corrección rechazada modificó al participante: %+v", p)
+	}
+
+	status, payload := s.do("PATCH", "/participants/ELI-001", "", `nombre: "Eva"; apellido_materno: "Ruiz";`)
+	if status != http.StatusOK {
+		t.Fatalf("corrección: %d %v", status, payload)
+	}
+	p := s.find("ELI-001")
+	if p.Nombre != "Eva" || p.ApellidoMaterno != "Ruiz" || p.ApellidoPaterno != "López" || p.Categoria != "Elite" {
+		t.Errorf("participante corregido: %+v", p)
+	}
+}
+
+func TestUpdateCategoryChangesCode(t *testing.T) {
+	s := newTestServer(t)
+	s.register(registration("ana@unam.mx"))
+
+	status, payload := s.do("PATCH", "/participants/ELI-001", "", `fecha_nacimiento: 1980-01-01;`)
+	if status != http.StatusOK {
+		t.Fatalf("corrección: %d %v", status, payload)
+	}
+	p := s.find("MASB-001")
+	if p.Categoria != "Master B" || p.FechaNacimiento != "1980-01-01" {
+		t.Errorf("participante corregido: %+v", p)
+	}
+	if status, _ := s.do("PATCH", "/participants/ELI-001", "", `nombre: "Eva";`); status != http.StatusNotFound {
+		t.Errorf("el código anterior respondió %d, se esperaba 404", status)
+	}
+}
+
+// TestConcurrentUpdates comprueba que dos correcciones simultáneas de campos
+// distintos se conservan las dos.
+func TestConcurrentUpdates(t *testing.T) {
+	s := newTestServer(t)
+	s.register(registration("ana@unam.mx"))
+
+	var wg sync.WaitGroup
+	for _, body := range []string{`nombre: "Eva";`, `apellido_materno: "Ruiz";`} {
+		wg.Add(1)
+		go func(body string) {
+			defer wg.Done()
+			req := httptest.NewRequest("PATCH", "/participants/ELI-001", strings.NewReader(body))
+			w := httptest.NewRecorder()
+			s.mux.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				t.Errorf("corrección %s: %d %s", body, w.Code, w.Body.String())
+			}
+		}(body)
+	}
+	wg.Wait()
+
+	if p := s.find("ELI-001"); p.Nombre != "Eva" || p.ApellidoMaterno != "Ruiz" {
+		t.Errorf("se perdió una corrección: %+v", p)
+	}
+}
+
+func TestUpdateKeepsOpenCategory(t *testing.T) {
+	s := newTestServer(t)
+	s.register(`nombre: "Ana"; apellido_paterno: "López"; email: "ana@unam.mx"; sexo: "F"; categoria: "Aficionado";`)
+
+	status, payload := s.do("PATCH", "/participants/AFI-001", "", `fecha_nacimiento: 1960-01-01;`)
+	if status != http.StatusOK {
+		t.Fatalf("corrección: %d %v", status, payload)
+	}
+	if p := s.find("AFI-001"); p.Categoria != "Aficionado" {
+		t.Errorf("la corrección cambió la categoría abierta a %q", p.Categoria)
+	}
+
+	status, _ = s.do("PATCH", "/participants/AFI-001", "", `categoria: "Elite"; fecha_nacimiento: 2000-01-01;`)
+	if status != http.StatusOK {
+		t.Errorf("cambio a una categoría por edad con fecha de nacimiento: %d", status)
+	}
+}
+
+func TestRegisterAgeCategoryRequiresBirth(t *testing.T) {
+	s := newTestServer(t)
+	status, payload := s.do("POST", "/register", "", `nombre: "Ana"; apellido_paterno: "López"; email: "ana@unam.mx"; sexo: "F"; categoria: "Elite";`)
+	if status != http.StatusBadRequest {
+		t.Errorf("categoría por edad sin fecha de nacimiento: %d %v, se esperaba 400", status, payload)
+	}
+}
+
+func TestSchemaAsOfRegistration(t *testing.T) {
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := schemaAsOf(models.Participant{CreatedAt: &created})
+	if !s.RaceDate.Equal(created) {
+		t.Errorf("RaceDate = %v, se esperaba la fecha de registro %v", s.RaceDate, created)
+	}
+	if semantic.ActiveSchema().RaceDate.Equal(created) {
+		t.Error("schemaAsOf modificó el esquema activo")
+	}
+}
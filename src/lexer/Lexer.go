@@ -1,95 +1,392 @@
 package lexer
 
-import "compilerciclista/src/token"
+import (
+	"compilerciclista/src/diagnostic"
+	"compilerciclista/src/token"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
 
 type Lexer struct {
 	input        string
 	position     int  // posición actual en el input (apunta al caracter actual)
 	readPosition int  // posición de lectura actual (después del caracter actual)
-	ch           byte // caracter actual bajo examinación
+	ch           rune // caracter actual bajo examinación
+	line         int  // línea del caracter actual (empieza en 1)
+	column       int  // columna del caracter actual, contada en runas (empieza en 1)
+
+	errors   []diagnostic.Diagnostic
+	comments []token.Token
 }
 
 func New(input string) *Lexer {
-	l := &Lexer{input: input}
+	l := &Lexer{input: input, line: 1}
 	l.readChar()
 	return l
 }
 
-// Lee el siguiente caracter y avanza la posición
+// Errors devuelve los problemas léxicos encontrados hasta el momento.
+// Cada uno corresponde a un token ILLEGAL emitido por NextToken.
+func (l *Lexer) Errors() []diagnostic.Diagnostic {
+	return l.errors
+}
+
+// Comments devuelve los comentarios encontrados hasta el momento, en orden.
+// El Literal de cada uno incluye los delimitadores ("# ..." o "/* ... */").
+func (l *Lexer) Comments() []token.Token {
+	return l.comments
+}
+
+// Text devuelve el texto original que ocupa span en el documento.
+func (l *Lexer) Text(span token.Span) string {
+	return l.input[span.Start.Offset:span.End.Offset]
+}
+
+// Lee el siguiente caracter (runa UTF-8) y avanza la posición
 func (l *Lexer) readChar() {
+	if l.ch == '\n' {
+		l.line++
+		l.column = 0
+	}
+	l.position = l.readPosition
 	if l.readPosition >= len(l.input) {
 		l.ch = 0 // NUL char, significa EOF
+		l.readPosition = len(l.input)
 	} else {
-		l.ch = l.input[l.readPosition]
+		r, size := utf8.DecodeRuneInString(l.input[l.readPosition:])
+		l.ch = r
+		l.readPosition += size
 	}
-	l.position = l.readPosition
-	l.readPosition++
+	l.column++
+}
+
+// peekChar devuelve el caracter siguiente sin avanzar.
+func (l *Lexer) peekChar() rune {
+	if l.readPosition >= len(l.input) {
+		return 0
+	}
+	r, _ := utf8.DecodeRuneInString(l.input[l.readPosition:])
+	return r
+}
+
+// atEOF distingue el final del input de un caracter NUL literal.
+func (l *Lexer) atEOF() bool {
+	return l.position >= len(l.input)
+}
+
+// pos devuelve la posición del caracter actual.
+func (l *Lexer) pos() token.Position {
+	return token.Position{Offset: l.position, Line: l.line, Column: l.column}
 }
 
 // La función principal que retorna el siguiente token
 func (l *Lexer) NextToken() token.Token {
 	var tok token.Token
+	var issue *diagnostic.Diagnostic
 
-	l.skipWhitespace()
+	start := l.skipWhitespaceAndComments()
+	if l.ch == '/' && l.peekChar() == '*' && !l.closesBlockComment() {
+		tok, issue = l.readUnterminatedComment()
+	} else {
+		tok, issue = l.scanToken()
+	}
+	tok.Span = token.Span{Start: start, End: l.pos()}
+	if issue != nil {
+		issue.Span = tok.Span
+		l.errors = append(l.errors, *issue)
+	}
+	return tok
+}
 
-	switch l.ch {
-	case ';':
+// scanToken reconoce el token que empieza en el caracter actual. Para los
+// tokens ILLEGAL devuelve además el diagnóstico que explica el problema;
+// NextToken le asigna el rango del token.
+func (l *Lexer) scanToken() (token.Token, *diagnostic.Diagnostic) {
+	var tok token.Token
+
+	switch {
+	case l.ch == ';':
 		tok = newToken(token.SEMICOLON, l.ch)
-	case ':':
+	case l.ch == ':':
 		tok = newToken(token.COLON, l.ch)
-	case '"':
-		tok.Type = token.STRING
-		tok.Literal = l.readString()
-	case 0:
+	case l.ch == ',':
+		tok = newToken(token.COMMA, l.ch)
+	case l.ch == '[':
+		tok = newToken(token.LBRACKET, l.ch)
+	case l.ch == ']':
+		tok = newToken(token.RBRACKET, l.ch)
+	case l.ch == '{':
+		tok = newToken(token.LBRACE, l.ch)
+	case l.ch == '}':
+		tok = newToken(token.RBRACE, l.ch)
+	case l.ch == '"':
+		return l.readString()
+	case l.atEOF():
 		tok.Literal = ""
 		tok.Type = token.EOF
-	default:
-		if isLetter(l.ch) {
-			tok.Literal = l.readIdentifier()
-			if tok.Literal == "true" || tok.Literal == "false" {
-				tok.Type = token.BOOL
-			} else {
-				tok.Type = token.IDENT
-			}
-			return tok
+		return tok, nil
+	case isLetter(l.ch):
+		tok.Literal = l.readIdentifier()
+		if tok.Literal == "true" || tok.Literal == "false" {
+			tok.Type = token.BOOL
 		} else {
-			tok = newToken(token.ILLEGAL, l.ch)
+			tok.Type = token.IDENT
 		}
+		return tok, nil
+	case isDigit(l.ch) || l.ch == '-' && isDigit(l.peekChar()):
+		return l.readNumber()
+	default:
+		tok = newToken(token.ILLEGAL, l.ch)
+		l.readChar()
+		return tok, problem(diagnostic.LexUnexpectedChar, tok.Literal)
 	}
 
 	l.readChar()
-	return tok
+	return tok, nil
 }
 
-func (l *Lexer) readString() string {
-	position := l.position + 1
+// readString lee una cadena entre comillas dobles interpretando las secuencias
+// de escape. Una cadena que llega al final de la línea o del documento sin
+// cerrarse produce un token ILLEGAL con el texto leído hasta ese punto.
+func (l *Lexer) readString() (token.Token, *diagnostic.Diagnostic) {
+	start := l.position
+	var out strings.Builder
+	var escapeErr *diagnostic.Diagnostic
+
+	l.readChar() // saltamos la comilla de apertura
 	for {
+		switch {
+		case l.atEOF() || l.ch == '\n':
+			return token.Token{Type: token.ILLEGAL, Literal: l.input[start:l.position]},
+				problem(diagnostic.LexUnterminatedString)
+		case l.ch == '"':
+			l.readChar()
+			if escapeErr != nil {
+				return token.Token{Type: token.ILLEGAL, Literal: l.input[start:l.position]}, escapeErr
+			}
+			return token.Token{Type: token.STRING, Literal: out.String()}, nil
+		case l.ch == '\\':
+			l.readChar()
+			if r, ok := l.readEscape(); ok {
+				out.WriteRune(r)
+			} else if escapeErr == nil {
+				escapeErr = problem(diagnostic.LexInvalidEscape)
+			}
+		default:
+			out.WriteRune(l.ch)
+			l.readChar()
+		}
+	}
+}
+
+// readEscape interpreta el caracter que sigue a una barra invertida.
+// Soporta \" \\ \n \t \r y \uXXXX.
+func (l *Lexer) readEscape() (rune, bool) {
+	var r rune
+	switch l.ch {
+	case '"':
+		r = '"'
+	case '\\':
+		r = '\\'
+	case 'n':
+		r = '\n'
+	case 't':
+		r = '\t'
+	case 'r':
+		r = '\r'
+	case 'u':
+		return l.readUnicodeEscape()
+	default:
+		if l.atEOF() || l.ch == '\n' {
+			return 0, false
+		}
 		l.readChar()
-		if l.ch == '"' || l.ch == 0 {
-			break
+		return 0, false
+	}
+	l.readChar()
+	return r, true
+}
+
+// readUnicodeEscape lee los cuatro dígitos hexadecimales de un escape \uXXXX.
+func (l *Lexer) readUnicodeEscape() (rune, bool) {
+	var r rune
+	for i := 0; i < 4; i++ {
+		if !isHexDigit(l.peekChar()) {
+			l.readChar()
+			return 0, false
 		}
+		l.readChar()
+		r = r<<4 | hexValue(l.ch)
+	}
+	l.readChar()
+	if !utf8.ValidRune(r) {
+		return 0, false
+	}
+	return r, true
+}
+
+// readNumber lee un entero (42, -3), un decimal (71.5) o una fecha con el
+// formato AAAA-MM-DD. La validez del calendario se comprueba en el parser.
+func (l *Lexer) readNumber() (token.Token, *diagnostic.Diagnostic) {
+	start := l.position
+	negative := l.ch == '-'
+	if negative {
+		l.readChar()
+	}
+	l.readDigits()
+
+	switch {
+	case l.ch == '-' && !negative && isDigit(l.peekChar()):
+		// Las fechas empiezan como un entero seguido de '-'.
+		l.readChar()
+		l.readDigits()
+		if l.ch == '-' && isDigit(l.peekChar()) {
+			l.readChar()
+			l.readDigits()
+		}
+		literal := l.input[start:l.position]
+		if !isDateLiteral(literal) {
+			return token.Token{Type: token.ILLEGAL, Literal: literal},
+				problem(diagnostic.LexMalformedDate, literal)
+		}
+		return token.Token{Type: token.DATE, Literal: literal}, nil
+	case l.ch == '.' && isDigit(l.peekChar()):
+		l.readChar()
+		l.readDigits()
+		return token.Token{Type: token.FLOAT, Literal: l.input[start:l.position]}, nil
+	default:
+		return token.Token{Type: token.INT, Literal: l.input[start:l.position]}, nil
+	}
+}
+
+func (l *Lexer) readDigits() {
+	for isDigit(l.ch) {
+		l.readChar()
 	}
-	return l.input[position:l.position]
 }
 
 func (l *Lexer) readIdentifier() string {
 	position := l.position
-	for isLetter(l.ch) {
+	for isLetter(l.ch) || isDigit(l.ch) {
 		l.readChar()
 	}
 	return l.input[position:l.position]
 }
 
+// skipWhitespaceAndComments avanza hasta el inicio del siguiente token,
+// guardando los comentarios que encuentre. Devuelve la posición del token.
+// Un comentario de bloque sin cerrar se deja para que NextToken lo reporte.
+func (l *Lexer) skipWhitespaceAndComments() token.Position {
+	for {
+		l.skipWhitespace()
+		switch {
+		case l.ch == '#':
+			l.readLineComment()
+		case l.ch == '/' && l.peekChar() == '*' && l.closesBlockComment():
+			l.readBlockComment()
+		default:
+			return l.pos()
+		}
+	}
+}
+
+// readLineComment lee un comentario desde '#' hasta el final de la línea.
+func (l *Lexer) readLineComment() {
+	start := l.pos()
+	for !l.atEOF() && l.ch != '\n' {
+		l.readChar()
+	}
+	l.addComment(start)
+}
+
+// readBlockComment lee un comentario /* ... */ que sabemos que está cerrado.
+func (l *Lexer) readBlockComment() {
+	start := l.pos()
+	l.readChar()
+	l.readChar()
+	for !(l.ch == '*' && l.peekChar() == '/') {
+		l.readChar()
+	}
+	l.readChar()
+	l.readChar()
+	l.addComment(start)
+}
+
+// readUnterminatedComment consume un comentario /* que nunca se cierra.
+func (l *Lexer) readUnterminatedComment() (token.Token, *diagnostic.Diagnostic) {
+	start := l.position
+	for !l.atEOF() {
+		l.readChar()
+	}
+	return token.Token{Type: token.ILLEGAL, Literal: l.input[start:l.position]},
+		problem(diagnostic.LexUnterminatedComment)
+}
+
+// closesBlockComment indica si el comentario que empieza en el caracter
+// actual tiene su '*/' de cierre.
+func (l *Lexer) closesBlockComment() bool {
+	return strings.Contains(l.input[l.position+2:], "*/")
+}
+
+func (l *Lexer) addComment(start token.Position) {
+	end := l.pos()
+	l.comments = append(l.comments, token.Token{
+		Type:    token.COMMENT,
+		Literal: strings.TrimRight(l.input[start.Offset:end.Offset], "\r"),
+		Span:    token.Span{Start: start, End: end},
+	})
+}
+
 func (l *Lexer) skipWhitespace() {
-	for l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r' {
+	for l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r' || l.ch == '\uFEFF' {
 		l.readChar()
 	}
 }
 
-func newToken(tokenType token.TokenType, ch byte) token.Token {
+// problem crea el diagnóstico de un token ILLEGAL; el rango se asigna en NextToken.
+func problem(code diagnostic.Code, args ...interface{}) *diagnostic.Diagnostic {
+	d := diagnostic.New(code, token.Span{}, args...)
+	return &d
+}
+
+func newToken(tokenType token.TokenType, ch rune) token.Token {
 	return token.Token{Type: tokenType, Literal: string(ch)}
 }
 
-func isLetter(ch byte) bool {
-	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
-}
\ No newline at end of file
+func isLetter(ch rune) bool {
+	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_' ||
+		ch >= utf8.RuneSelf && unicode.IsLetter(ch)
+}
+
+func isDigit(ch rune) bool {
+	return '0' <= ch && ch <= '9'
+}
+
+// isDateLiteral comprueba la forma AAAA-MM-DD (solo dígitos y guiones).
+func isDateLiteral(s string) bool {
+	if len(s) != 10 || s[4] != '-' || s[7] != '-' {
+		return false
+	}
+	for i, c := range s {
+		if i != 4 && i != 7 && !isDigit(c) {
+			return false
+		}
+	}
+	return true
+}
+
+func isHexDigit(ch rune) bool {
+	return '0' <= ch && ch <= '9' || 'a' <= ch && ch <= 'f' || 'A' <= ch && ch <= 'F'
+}
+
+func hexValue(ch rune) rune {
+	switch {
+	case '0' <= ch && ch <= '9':
+		return ch - '0'
+	case 'a' <= ch && ch <= 'f':
+		return ch - 'a' + 10
+	default:
+		return ch - 'A' + 10
+	}
+}
@@ -0,0 +1,81 @@
+package lexer
+
+import (
+	"compilerciclista/src/diagnostic"
+	"compilerciclista/src/token"
+	"testing"
+)
+
+func TestStringEscapes(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`"simple"`, "simple"},
+		{`"con \"comillas\""`, `con "comillas"`},
+		{`"barra \\ invertida"`, `barra \ invertida`},
+		{`"a\nb\tc\rd"`, "a\nb\tc\rd"},
+		{`"Mu\u00f1oz"`, "Muñoz"},
+		{`"\u00D1"`, "Ñ"},
+		{`"Peña"`, "Peña"},
+		{`""`, ""},
+	}
+
+	for _, tt := range tests {
+		l := New(tt.input)
+		tok := l.NextToken()
+		if tok.Type != token.STRING || tok.Literal != tt.want {
+			t.Errorf("New(%s).NextToken() = %s %q, se esperaba STRING %q", tt.input, tok.Type, tok.Literal, tt.want)
+		}
+		if len(l.Errors()) > 0 {
+			t.Errorf("New(%s) reportó errores: %v", tt.input, l.Errors())
+		}
+		if next := l.NextToken(); next.Type != token.EOF {
+			t.Errorf("New(%s): después de la cadena se esperaba EOF, se obtuvo %s %q", tt.input, next.Type, next.Literal)
+		}
+	}
+}
+
+func TestInvalidStrings(t *testing.T) {
+	tests := []struct {
+		input string
+		code  diagnostic.Code
+	}{
+		{`"a\qb"`, diagnostic.LexInvalidEscape},
+		{`"\u00g1"`, diagnostic.LexInvalidEscape},
+		{`"\u12"`, diagnostic.LexInvalidEscape},
+		{`"\uD800"`, diagnostic.LexInvalidEscape}, // sustituto, no es una runa válida
+		{`"sin cerrar`, diagnostic.LexUnterminatedString},
+		{"\"corta\nen la línea\"", diagnostic.LexUnterminatedString},
+		{`"termina en \`, diagnostic.LexUnterminatedString},
+	}
+
+	for _, tt := range tests {
+		l := New(tt.input)
+		tok := l.NextToken()
+		if tok.Type != token.ILLEGAL {
+			t.Errorf("New(%q).NextToken() = %s %q, se esperaba ILLEGAL", tt.input, tok.Type, tok.Literal)
+		}
+		errors := l.Errors()
+		if len(errors) != 1 || errors[0].Code != tt.code {
+			t.Errorf("New(%q): se esperaba un error %s, se obtuvo %v", tt.input, tt.code, errors)
+			continue
+		}
+		if errors[0].Span != tok.Span {
+			t.Errorf("New(%q): el error está en %v y el token en %v", tt.input, errors[0].Span, tok.Span)
+		}
+	}
+}
+
+func TestStringSpanCountsRunes(t *testing.T) {
+	l := New(`nombre: "Peñañ";`)
+	var tok token.Token
+	for tok = l.NextToken(); tok.Type != token.STRING; tok = l.NextToken() {
+	}
+	if tok.Span.Start.Column != 9 || tok.Span.End.Column != 16 {
+		t.Errorf("la cadena ocupa las columnas %d-%d, se esperaba 9-16", tok.Span.Start.Column, tok.Span.End.Column)
+	}
+	if got := l.Text(tok.Span); got != `"Peñañ"` {
+		t.Errorf("Text = %q", got)
+	}
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEntry es un registro de la auditoría de participantes: quién hizo qué
+// operación, cuándo, en qué solicitud y cómo quedó el participante. Las
+// entradas nunca se modifican ni se borran.
+type AuditEntry struct {
+	ID            int64           `json:"id"`
+	ParticipantID int64           `json:"participant_id"`
+	Action        string          `json:"action"`
+	Actor         string          `json:"actor"`
+	Before        json.RawMessage `json:"before,omitempty"` // vacío en el registro
+	After         json.RawMessage `json:"after,omitempty"`
+	RequestID     string          `json:"request_id,omitempty"`
+	CreatedAt     *time.Time      `json:"created_at,omitempty"`
+}
+
+// Acciones que registra la auditoría.
+const (
+	AccionRegistro     = "registro"
+	AccionCorreccion   = "correccion"
+	AccionPago         = "pago"
+	AccionDocumentos   = "documentos"
+	AccionBaja         = "baja"
+	AccionReactivacion = "reactivacion"
+)
@@ -0,0 +1,9 @@
+package models
+
+// Change es la modificación de un campo de un participante, con sus valores
+// anterior y nuevo.
+type Change struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
@@ -1,15 +1,45 @@
 package models
 
+import "time"
+
 type Participant struct {
-	ID                  int64  `json:"id"`
-	ParticipantCode     string `json:"participant_code"`
-	Nombre              string `json:"nombre"`
-	ApellidoPaterno     string `json:"apellido_paterno"`
-	ApellidoMaterno     string `json:"apellido_materno,omitempty"`
-	Email               string `json:"email"`
-	Sexo                string `json:"sexo"`
-	Categoria           string `json:"categoria"`
-	PagoRealizado       bool   `json:"pago_realizado"`
-	InePath             string `json:"ine_path,omitempty"`
-	ComprobantePagoPath string `json:"comprobante_pago_path,omitempty"`
-}
\ No newline at end of file
+	ID                  int64      `json:"id"`
+	ParticipantCode     string     `json:"participant_code"`
+	Evento              string     `json:"evento,omitempty"`
+	Nombre              string     `json:"nombre"`
+	ApellidoPaterno     string     `json:"apellido_paterno"`
+	ApellidoMaterno     string     `json:"apellido_materno,omitempty"`
+	Email               string     `json:"email"`
+	Sexo                string     `json:"sexo"`
+	FechaNacimiento     string     `json:"fecha_nacimiento,omitempty"` // AAAA-MM-DD
+	CURP                string     `json:"curp,omitempty"`
+	Categoria           string     `json:"categoria"`
+	PagoRealizado       bool       `json:"pago_realizado"`
+	Estado              string     `json:"estado"`
+	InePath             string     `json:"ine_path,omitempty"`
+	ComprobantePagoPath string     `json:"comprobante_pago_path,omitempty"`
+	CreatedAt           *time.Time `json:"created_at,omitempty"`
+}
+
+// Estados de una inscripción. Solo las activas aparecen en las listas y
+// cuentan en los totales; las demás se conservan, con su código, para poder
+// reactivarlas.
+const (
+	EstadoActivo        = "activo"
+	EstadoRetirado      = "retirado"
+	EstadoCancelado     = "cancelado"
+	EstadoDescalificado = "descalificado"
+)
+
+// Estados enumera los estados válidos, empezando por el inicial.
+var Estados = []string{EstadoActivo, EstadoRetirado, EstadoCancelado, EstadoDescalificado}
+
+// IsEstado indica si s es uno de los estados válidos.
+func IsEstado(s string) bool {
+	for _, estado := range Estados {
+		if s == estado {
+			return true
+		}
+	}
+	return false
+}
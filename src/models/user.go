@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// User es una cuenta para entrar a la API con usuario y contraseña.
+type User struct {
+	ID            int64      `json:"id"`
+	Username      string     `json:"username"`
+	PasswordHash  string     `json:"-"` // bcrypt
+	Rol           string     `json:"rol"`
+	ParticipantID int64      `json:"participant_id,omitempty"` // solo para corredores
+	CreatedAt     *time.Time `json:"created_at,omitempty"`
+}
+
+// Roles de las cuentas. Los corredores también reciben un token con este rol
+// al registrarse.
+const (
+	RolAdmin       = "admin"
+	RolOrganizador = "organizador"
+	RolMesa        = "mesa_registro"
+	RolJuez        = "juez"
+	RolCorredor    = "corredor"
+)
+
+// Roles enumera los roles válidos.
+var Roles = []string{RolAdmin, RolOrganizador, RolMesa, RolJuez, RolCorredor}
+
+// IsRol indica si s es uno de los roles válidos.
+func IsRol(s string) bool {
+	for _, rol := range Roles {
+		if s == rol {
+			return true
+		}
+	}
+	return false
+}
+
+// DeskRoles son los roles que registran participantes y pagos en nombre de
+// otros: corrigen inscripciones, registran lotes y marcan pagos.
+var DeskRoles = []string{RolAdmin, RolOrganizador, RolMesa}
+
+// IsStaff indica si el rol pertenece a la organización del evento.
+func IsStaff(rol string) bool {
+	return IsRol(rol) && rol != RolCorredor
+}
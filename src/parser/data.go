@@ -0,0 +1,21 @@
+package parser
+
+import "compilerciclista/src/ast"
+
+// DataFromStatements deriva del árbol el mapa de datos que usan las capas
+// semántica y de persistencia, junto con la ubicación de cada valor. Si una
+// clave aparece más de una vez prevalece la primera, igual que al reportarla.
+func DataFromStatements(statements []*ast.Statement) (ParticipantData, Spans) {
+	data := make(ParticipantData, len(statements))
+	spans := make(Spans, len(statements))
+
+	for _, stmt := range statements {
+		if _, seen := data[stmt.Key.Name]; seen {
+			continue
+		}
+		data[stmt.Key.Name] = stmt.Value.GoValue()
+		spans[stmt.Key.Name] = stmt.Value.Span()
+	}
+
+	return data, spans
+}
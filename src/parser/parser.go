@@ -1,17 +1,48 @@
 package parser
 
 import (
+	"compilerciclista/src/ast"
+	"compilerciclista/src/diagnostic"
 	"compilerciclista/src/lexer"
 	"compilerciclista/src/token"
-	"fmt"
+	"sort"
 	"strconv"
+	"time"
 )
 
 type ParticipantData map[string]interface{}
 
+// DateLayout es el formato de las fechas literales del DSL (AAAA-MM-DD).
+const DateLayout = "2006-01-02"
+
+// Spans guarda, para cada clave del documento, el rango del valor asignado.
+type Spans map[string]token.Span
+
+// MaxErrors es el máximo de errores que se reportan por documento. Al
+// alcanzarlo el parser deja de analizar el resto del texto.
+const MaxErrors = 20
+
+// RecordKeyword es el nombre de los bloques de un documento de lote.
+const RecordKeyword = "participante"
+
+// Record es un bloque 'participante { ... }' de un documento de lote.
+type Record struct {
+	Data  ParticipantData
+	Spans Spans
+	Span  token.Span // rango del bloque completo
+}
+
 type Parser struct {
 	l      *lexer.Lexer
-	errors []string
+	errors []diagnostic.Diagnostic
+	spans  Spans
+
+	// field es la clave de la declaración que se está parseando.
+	field string
+
+	// abortedAt es el token en el que se dejó de parsear por exceso de errores.
+	aborted   bool
+	abortedAt token.Span
 
 	curToken  token.Token
 	peekToken token.Token
@@ -20,15 +51,37 @@ type Parser struct {
 func New(l *lexer.Lexer) *Parser {
 	p := &Parser{
 		l:      l,
-		errors: []string{},
+		errors: []diagnostic.Diagnostic{},
+		spans:  make(Spans),
 	}
 	p.nextToken()
 	p.nextToken()
 	return p
 }
 
-func (p *Parser) Errors() []string {
-	return p.errors
+// Errors devuelve los errores léxicos y sintácticos ordenados por posición.
+func (p *Parser) Errors() []diagnostic.Diagnostic {
+	all := append([]diagnostic.Diagnostic{}, p.l.Errors()...)
+	all = append(all, p.errors...)
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].Span.Start.Offset < all[j].Span.Start.Offset
+	})
+
+	if len(all) > MaxErrors || p.aborted {
+		span := p.abortedAt
+		if len(all) > MaxErrors {
+			span = all[MaxErrors].Span
+			all = all[:MaxErrors]
+		}
+		all = append(all, diagnostic.New(diagnostic.ParTooManyErrors, span, MaxErrors))
+	}
+	return all
+}
+
+// Spans devuelve la ubicación de cada valor parseado, para que las capas
+// posteriores puedan señalar el texto exacto al reportar un error.
+func (p *Parser) Spans() Spans {
+	return p.spans
 }
 
 func (p *Parser) nextToken() {
@@ -37,59 +90,278 @@ func (p *Parser) nextToken() {
 }
 
 // ParseProgram es el punto de entrada principal
-func (p *Parser) ParseProgram() (ParticipantData, []string) {
-	data := make(ParticipantData)
+func (p *Parser) ParseProgram() (ParticipantData, []diagnostic.Diagnostic) {
+	doc := p.parseDocument(false)
+	data, spans := DataFromStatements(doc.Statements)
+	p.spans = spans
+	return data, p.Errors()
+}
+
+// ParseBatch parsea un documento de lote formado por bloques repetidos:
+//
+//	participante { nombre: "Ana"; ... }
+//	participante { nombre: "Luis"; ... }
+//
+// Cada bloque produce un Record con sus propios datos y ubicaciones.
+func (p *Parser) ParseBatch() ([]Record, []diagnostic.Diagnostic) {
+	doc := p.parseDocument(true)
+
+	records := make([]Record, 0, len(doc.Blocks))
+	for _, block := range doc.Blocks {
+		if block.Token.Literal != RecordKeyword {
+			p.addError(diagnostic.ParUnexpectedBlock, block.Token.Span, RecordKeyword, block.Token.Literal)
+			continue
+		}
+		data, spans := DataFromStatements(block.Statements)
+		records = append(records, Record{Data: data, Spans: spans, Span: block.Span()})
+	}
+
+	return records, p.Errors()
+}
+
+// ParseDocument construye el árbol sintáctico completo, conservando el orden,
+// las posiciones, el texto original y los comentarios. Si el documento empieza
+// con un bloque 'nombre {' se interpreta como documento de bloques.
+func (p *Parser) ParseDocument() (*ast.Document, []diagnostic.Diagnostic) {
+	doc := p.parseDocument(p.atBlockStart())
+	return doc, p.Errors()
+}
+
+// ParseBlocks construye el árbol de un documento formado solo por bloques,
+// como un lote de participantes o un esquema de evento.
+func (p *Parser) ParseBlocks() (*ast.Document, []diagnostic.Diagnostic) {
+	doc := p.parseDocument(true)
+	return doc, p.Errors()
+}
+
+func (p *Parser) parseDocument(blocks bool) *ast.Document {
+	doc := &ast.Document{}
 
+	if blocks {
+		for p.curToken.Type != token.EOF && !p.checkErrorLimit() {
+			if block := p.parseBlock(); block != nil {
+				doc.Blocks = append(doc.Blocks, block)
+				p.nextToken()
+			} else {
+				p.synchronizeBlock()
+			}
+		}
+	} else {
+		doc.Statements = p.parseStatements(token.EOF)
+	}
+
+	// Al abandonar por exceso de errores se lee el resto para conservar los comentarios.
 	for p.curToken.Type != token.EOF {
-		key, value := p.parseStatement()
-		if key != "" {
-			data[key] = value
+		p.nextToken()
+	}
+	doc.EOF = p.curToken
+	doc.Comments = p.l.Comments()
+	return doc
+}
+
+// parseStatements parsea declaraciones hasta encontrar el token end (o EOF).
+// Las claves repetidas se reportan como error pero se conservan en el árbol.
+func (p *Parser) parseStatements(end token.TokenType) []*ast.Statement {
+	var statements []*ast.Statement
+	keys := make(map[string]token.Span) // ubicación de cada clave ya vista
+
+	for p.curToken.Type != end && p.curToken.Type != token.EOF && !p.checkErrorLimit() {
+		// Dentro de un bloque, el inicio de otro bloque indica que faltó la '}'.
+		if end == token.RBRACE && p.atBlockStart() {
+			break
+		}
+
+		stmt := p.parseStatement()
+		if stmt == nil {
+			p.synchronize(end)
+			continue
+		}
+
+		if previous, duplicated := keys[stmt.Key.Name]; duplicated {
+			p.errors = append(p.errors, diagnostic.New(diagnostic.ParDuplicateKey, stmt.Key.Span(), stmt.Key.Name, previous.Start.Line).WithField(stmt.Key.Name))
+		} else {
+			keys[stmt.Key.Name] = stmt.Key.Span()
 		}
+		statements = append(statements, stmt)
 		p.nextToken()
 	}
 
-	return data, p.errors
+	return statements
+}
+
+// Parsea un bloque completo, ej: participante { nombre: "Juan"; }
+func (p *Parser) parseBlock() *ast.Block {
+	if p.curToken.Type != token.IDENT {
+		p.errorAt(p.curToken, diagnostic.ParExpectedBlock, p.curToken.Literal)
+		return nil
+	}
+	block := &ast.Block{Token: p.curToken}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	p.nextToken()
+
+	block.Statements = p.parseStatements(token.RBRACE)
+	if p.curToken.Type != token.RBRACE {
+		p.errorAt(p.curToken, diagnostic.ParUnclosedBlock, block.Token.Literal)
+		return nil
+	}
+	block.RBrace = p.curToken
+
+	return block
+}
+
+// synchronize descarta tokens después de un error hasta un punto seguro para
+// continuar: el token que sigue al próximo ';', o un identificador al inicio de
+// una línea nueva (probablemente la siguiente declaración). Así un solo error
+// produce un solo diagnóstico en lugar de una cascada.
+func (p *Parser) synchronize(end token.TokenType) {
+	for p.curToken.Type != end && p.curToken.Type != token.EOF {
+		if p.curToken.Type == token.SEMICOLON {
+			p.nextToken()
+			return
+		}
+		line := p.curToken.Span.End.Line
+		p.nextToken()
+		if p.curToken.Type == token.IDENT && p.curToken.Span.Start.Line > line {
+			return
+		}
+	}
+}
+
+// synchronizeBlock descarta tokens hasta el inicio del siguiente bloque.
+func (p *Parser) synchronizeBlock() {
+	if p.atBlockStart() {
+		return
+	}
+	for p.curToken.Type != token.EOF {
+		p.nextToken()
+		if p.atBlockStart() {
+			return
+		}
+	}
+}
+
+// atBlockStart indica si el token actual abre un bloque 'nombre {'.
+func (p *Parser) atBlockStart() bool {
+	return p.curToken.Type == token.IDENT && p.peekToken.Type == token.LBRACE
+}
+
+// checkErrorLimit indica si ya se alcanzó MaxErrors, en cuyo caso el parser
+// abandona el documento.
+func (p *Parser) checkErrorLimit() bool {
+	if len(p.errors)+len(p.l.Errors()) < MaxErrors {
+		return false
+	}
+	if !p.aborted {
+		p.aborted = true
+		p.abortedAt = p.curToken.Span
+	}
+	return true
 }
 
 // Parsea una declaración completa, ej: nombre: "Juan";
-func (p *Parser) parseStatement() (string, interface{}) {
+// Devuelve nil si la declaración tiene un error de sintaxis.
+func (p *Parser) parseStatement() *ast.Statement {
 	// Debe empezar con un identificador (la clave)
 	if p.curToken.Type != token.IDENT {
-		p.errors = append(p.errors, fmt.Sprintf("Error de sintaxis: se esperaba un identificador, se obtuvo %s", p.curToken.Literal))
-		return "", nil
+		p.errorAt(p.curToken, diagnostic.ParExpectedIdent, p.curToken.Literal)
+		return nil
 	}
-	key := p.curToken.Literal
+	stmt := &ast.Statement{Key: &ast.Key{Token: p.curToken, Name: p.curToken.Literal}}
+	p.field = stmt.Key.Name
+	defer func() { p.field = "" }()
 
 	// Después debe venir un ':'
 	if !p.expectPeek(token.COLON) {
-		return "", nil
+		return nil
 	}
 
 	p.nextToken() // Avanzamos al valor
 
 	// Parseamos el valor
-	var value interface{}
-	switch p.curToken.Type {
+	stmt.Value = p.parseValue()
+	if stmt.Value == nil {
+		return nil
+	}
+
+	// La declaración debe terminar con ';'
+	if !p.expectPeek(token.SEMICOLON) {
+		return nil
+	}
+	stmt.Semicolon = p.curToken
+
+	return stmt
+}
+
+// parseValue construye el nodo del valor que empieza en el token actual.
+// Devuelve nil si el valor no es válido.
+func (p *Parser) parseValue() ast.Value {
+	tok := p.curToken
+	switch tok.Type {
 	case token.STRING:
-		value = p.curToken.Literal
+		return &ast.StringLiteral{Token: tok, Value: tok.Literal, Raw: p.l.Text(tok.Span)}
 	case token.BOOL:
-		boolValue, err := strconv.ParseBool(p.curToken.Literal)
+		boolValue, err := strconv.ParseBool(tok.Literal)
+		if err != nil {
+			p.addError(diagnostic.ParInvalidBool, tok.Span, tok.Literal)
+			return nil
+		}
+		return &ast.BooleanLiteral{Token: tok, Value: boolValue}
+	case token.INT:
+		intValue, err := strconv.ParseInt(tok.Literal, 10, 64)
+		if err != nil {
+			p.addError(diagnostic.ParIntOutOfRange, tok.Span, tok.Literal)
+			return nil
+		}
+		return &ast.IntegerLiteral{Token: tok, Value: intValue}
+	case token.FLOAT:
+		floatValue, err := strconv.ParseFloat(tok.Literal, 64)
+		if err != nil {
+			p.addError(diagnostic.ParInvalidFloat, tok.Span, tok.Literal)
+			return nil
+		}
+		return &ast.FloatLiteral{Token: tok, Value: floatValue}
+	case token.DATE:
+		dateValue, err := time.Parse(DateLayout, tok.Literal)
 		if err != nil {
-			p.errors = append(p.errors, fmt.Sprintf("Error de sintaxis: no se pudo convertir '%s' a booleano", p.curToken.Literal))
-			return "", nil
+			p.addError(diagnostic.ParInvalidDate, tok.Span, tok.Literal)
+			return nil
 		}
-		value = boolValue
+		return &ast.DateLiteral{Token: tok, Value: dateValue}
+	case token.LBRACKET:
+		return p.parseList()
 	default:
-		p.errors = append(p.errors, fmt.Sprintf("Error de sintaxis: se encontró un tipo de valor no válido %s", p.curToken.Type))
-		return "", nil
+		p.errorAt(tok, diagnostic.ParInvalidValue, tok.Type)
+		return nil
 	}
+}
 
-	// La declaración debe terminar con ';'
-	if !p.expectPeek(token.SEMICOLON) {
-		return "", nil
+// parseList parsea una lista entre corchetes, ej: ["555 123", "555 456"].
+// Se admite una coma final antes de ']'.
+func (p *Parser) parseList() ast.Value {
+	list := &ast.ListLiteral{Token: p.curToken, Elements: []ast.Value{}}
+
+	for p.peekToken.Type != token.RBRACKET {
+		p.nextToken()
+		item := p.parseValue()
+		if item == nil {
+			return nil
+		}
+		list.Elements = append(list.Elements, item)
+
+		if p.peekToken.Type != token.COMMA {
+			break
+		}
+		p.nextToken()
 	}
 
-	return key, value
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+	list.RBracket = p.curToken
+	return list
 }
 
 // expectPeek revisa el tipo del siguiente token. Si es correcto, avanza.
@@ -104,6 +376,27 @@ func (p *Parser) expectPeek(t token.TokenType) bool {
 }
 
 func (p *Parser) peekError(t token.TokenType) {
-	msg := fmt.Sprintf("Error de sintaxis: se esperaba el token %s, se obtuvo %s", t, p.peekToken.Type)
-	p.errors = append(p.errors, msg)
-}
\ No newline at end of file
+	// Si el token inesperado ya está en otra línea, el error se marca al final
+	// del token actual, que es donde realmente falta el que se esperaba.
+	if p.peekToken.Span.Start.Line > p.curToken.Span.End.Line && p.peekToken.Type != token.ILLEGAL {
+		end := p.curToken.Span.End
+		p.addError(diagnostic.ParExpectedToken, token.Span{Start: end, End: end}, t, p.peekToken.Type)
+		return
+	}
+	p.errorAt(p.peekToken, diagnostic.ParExpectedToken, t, p.peekToken.Type)
+}
+
+// errorAt registra un error sobre tok. Los tokens ILLEGAL ya fueron reportados
+// por el lexer con un mensaje más preciso, así que no se duplican.
+func (p *Parser) errorAt(tok token.Token, code diagnostic.Code, args ...interface{}) {
+	if tok.Type == token.ILLEGAL {
+		return
+	}
+	p.addError(code, tok.Span, args...)
+}
+
+// addError registra un error de sintaxis ubicado en el rango indicado. Si se
+// está parseando una declaración, el error queda asociado a su clave.
+func (p *Parser) addError(code diagnostic.Code, span token.Span, args ...interface{}) {
+	p.errors = append(p.errors, diagnostic.New(code, span, args...).WithField(p.field))
+}
@@ -0,0 +1,124 @@
+package parser
+
+import (
+	"compilerciclista/src/diagnostic"
+	"compilerciclista/src/lexer"
+	"strings"
+	"testing"
+)
+
+// codes devuelve los códigos de los diagnósticos, en orden.
+func codes(diagnostics []diagnostic.Diagnostic) []diagnostic.Code {
+	out := make([]diagnostic.Code, len(diagnostics))
+	for i, d := range diagnostics {
+		out[i] = d.Code
+	}
+	return out
+}
+
+func equalCodes(a, b []diagnostic.Code) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestParseProgramRecovery(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		codes []diagnostic.Code
+		lines []int
+		keys  []string // claves que sobreviven al error
+	}{
+		{
+			name:  "falta ':' y falta el valor",
+			input: "nombre \"Ana\";\nemail: \"a@b.mx\";\nsexo: ;\ncategoria: \"Elite\";",
+			codes: []diagnostic.Code{diagnostic.ParExpectedToken, diagnostic.ParInvalidValue},
+			lines: []int{1, 3},
+			keys:  []string{"email", "categoria"},
+		},
+		{
+			name:  "falta ';' al final de la línea",
+			input: "nombre: \"Ana\"\nemail: \"a@b.mx\";",
+			codes: []diagnostic.Code{diagnostic.ParExpectedToken},
+			lines: []int{1},
+			keys:  []string{"email"},
+		},
+		{
+			name:  "clave duplicada",
+			input: "nombre: \"A\";\nnombre: \"B\";\nemail: \"a@b.mx\";",
+			codes: []diagnostic.Code{diagnostic.ParDuplicateKey},
+			lines: []int{2},
+			keys:  []string{"nombre", "email"},
+		},
+		{
+			name:  "fecha imposible",
+			input: "fecha_nacimiento: 2001-02-30;\nnombre: \"Ana\";",
+			codes: []diagnostic.Code{diagnostic.ParInvalidDate},
+			lines: []int{1},
+			keys:  []string{"nombre"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, errors := New(lexer.New(tt.input)).ParseProgram()
+			if !equalCodes(codes(errors), tt.codes) {
+				t.Fatalf("errores %v, se esperaba %v", errors, tt.codes)
+			}
+			for i, line := range tt.lines {
+				if errors[i].Span.Start.Line != line {
+					t.Errorf("el error %s está en la línea %d, se esperaba %d", errors[i].Code, errors[i].Span.Start.Line, line)
+				}
+			}
+			for _, key := range tt.keys {
+				if _, ok := data[key]; !ok {
+					t.Errorf("falta la clave %q en %v", key, data)
+				}
+			}
+		})
+	}
+}
+
+func TestDuplicateKeyKeepsFirstValue(t *testing.T) {
+	data, errors := New(lexer.New("nombre: \"A\"; nombre: \"B\";")).ParseProgram()
+	if len(errors) != 1 || errors[0].Field != "nombre" {
+		t.Fatalf("se esperaba un PAR008 sobre 'nombre', se obtuvo %v", errors)
+	}
+	if data["nombre"] != "A" {
+		t.Errorf("nombre = %v, se esperaba el primer valor", data["nombre"])
+	}
+}
+
+func TestParseProgramMaxErrors(t *testing.T) {
+	input := strings.Repeat("x \"a\";\n", MaxErrors+10)
+	_, errors := New(lexer.New(input)).ParseProgram()
+	if len(errors) != MaxErrors+1 {
+		t.Fatalf("se obtuvieron %d errores, se esperaban %d más el aviso", len(errors), MaxErrors)
+	}
+	last := errors[MaxErrors]
+	if last.Code != diagnostic.ParTooManyErrors {
+		t.Errorf("el último diagnóstico es %s, se esperaba %s", last.Code, diagnostic.ParTooManyErrors)
+	}
+	if last.Span.Start.Line != MaxErrors+1 {
+		t.Errorf("el aviso está en la línea %d, se esperaba %d", last.Span.Start.Line, MaxErrors+1)
+	}
+}
+
+func TestParseBatchRecovery(t *testing.T) {
+	input := "participante { nombre: \"a\";\nparticipante { nombre: \"b\"; }\notro { x: 1; }\nparticipante { nombre: \"c\"; }"
+	records, errors := New(lexer.New(input)).ParseBatch()
+	want := []diagnostic.Code{diagnostic.ParUnclosedBlock, diagnostic.ParUnexpectedBlock}
+	if !equalCodes(codes(errors), want) {
+		t.Fatalf("errores %v, se esperaba %v", errors, want)
+	}
+	if len(records) != 2 || records[0].Data["nombre"] != "b" || records[1].Data["nombre"] != "c" {
+		t.Errorf("registros %v, se esperaban los bloques 'b' y 'c'", records)
+	}
+}
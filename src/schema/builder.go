@@ -0,0 +1,313 @@
+package schema
+
+import (
+	"compilerciclista/src/ast"
+	"compilerciclista/src/category"
+	"compilerciclista/src/diagnostic"
+	"compilerciclista/src/emailcheck"
+	"compilerciclista/src/parser"
+	"compilerciclista/src/token"
+	"regexp"
+	"time"
+)
+
+// builder convierte los bloques del documento en un Schema, acumulando los
+// errores de cada bloque.
+type builder struct {
+	schema *Schema
+	errors []diagnostic.Diagnostic
+	rules  []*ast.Block // bloque de cada regla, para ubicar sus errores
+	fields map[string]*ast.Block
+}
+
+// addError reporta un error sobre el bloque completo.
+func (b *builder) addError(block *ast.Block, code diagnostic.Code, args ...interface{}) {
+	b.errors = append(b.errors, diagnostic.New(code, block.Token.Span, args...))
+}
+
+func (b *builder) event(block *ast.Block) {
+	r := b.reader(block)
+	b.schema.Event = r.str("nombre", true)
+	b.schema.RaceDate = r.date("fecha")
+	b.schema.AutoCategory = r.boolean("asignar_categoria")
+	r.finish()
+}
+
+func (b *builder) field(block *ast.Block) {
+	r := b.reader(block)
+	f := &Field{
+		Name:     r.str("nombre", true),
+		Type:     Type(r.str("tipo", true)),
+		Required: r.boolean("requerido"),
+		Values:   r.strs("valores"),
+		Message:  r.str("mensaje", false),
+		Min:      r.number("minimo"),
+		Max:      r.number("maximo"),
+	}
+	if expr := r.str("patron", false); expr != "" {
+		pattern, err := regexp.Compile(expr)
+		if err != nil {
+			r.addError("patron", diagnostic.SchInvalidPattern, err)
+		}
+		f.Pattern = pattern
+	}
+	f.Format = r.str("formato", false)
+	emailKeys := []string{"dominios_permitidos", "dominios_bloqueados", "bloquear_desechables", "verificar_mx"}
+	if f.Format == FormatEmail {
+		f.Email = &emailcheck.Policy{
+			Allowed:         r.strs("dominios_permitidos"),
+			Denied:          r.strs("dominios_bloqueados"),
+			BlockDisposable: r.boolean("bloquear_desechables"),
+			CheckMX:         r.boolean("verificar_mx"),
+		}
+	} else {
+		if f.Format != "" && f.Format != FormatCURP {
+			r.addError("formato", diagnostic.SchUnknownFormat, f.Format)
+		}
+		for _, key := range emailKeys {
+			if _, present := r.data[key]; present {
+				r.used[key] = true
+				r.addError(key, diagnostic.SchNeedsEmail, key)
+			}
+		}
+	}
+	r.finish()
+
+	if f.Name == "" {
+		return // ya se reportó que falta
+	}
+	if f.Type != "" && !f.Type.IsValid() {
+		r.addError("tipo", diagnostic.SchUnknownType, f.Type)
+	}
+	if f.Type != String {
+		r.inapplicable(f.Type, "valores", "patron", "formato")
+	}
+	if f.Type != Int && f.Type != Float {
+		r.inapplicable(f.Type, "minimo", "maximo")
+	}
+
+	if _, exists := b.schema.byName[f.Name]; exists {
+		r.addError("nombre", diagnostic.SchDuplicateField, f.Name)
+		return
+	}
+	b.schema.Fields = append(b.schema.Fields, f)
+	b.schema.byName[f.Name] = f
+	b.fields[f.Name] = block
+}
+
+func (b *builder) rule(block *ast.Block) {
+	r := b.reader(block)
+	rule := &Rule{
+		If:       r.str("si", true),
+		Equals:   r.value("igual"),
+		Requires: r.str("requiere", true),
+		Message:  r.str("mensaje", false),
+	}
+	r.finish()
+
+	b.schema.Rules = append(b.schema.Rules, rule)
+	b.rules = append(b.rules, block)
+}
+
+func (b *builder) category(block *ast.Block) {
+	r := b.reader(block)
+	c := category.Category{
+		Name:   r.str("nombre", true),
+		MinAge: r.age("edad_minima"),
+		MaxAge: r.age("edad_maxima"),
+		Sexo:   r.str("sexo", false),
+		Open:   r.boolean("abierta"),
+	}
+	r.finish()
+
+	if c.MaxAge > 0 && c.MinAge > c.MaxAge {
+		r.addError("edad_minima", diagnostic.SchInvalidAgeRange, c.MinAge, c.MaxAge)
+		return
+	}
+	b.schema.Categories = append(b.schema.Categories, c)
+}
+
+// checkRules verifica, ya con todos los campos declarados, que las reglas
+// solo mencionen campos existentes.
+func (b *builder) checkRules() {
+	for i, rule := range b.schema.Rules {
+		r := b.reader(b.rules[i])
+		for _, ref := range []struct{ key, name string }{{"si", rule.If}, {"requiere", rule.Requires}} {
+			if _, ok := b.schema.byName[ref.name]; ref.name != "" && !ok {
+				r.addError(ref.key, diagnostic.SchUndeclaredField, ref.name)
+			}
+		}
+	}
+}
+
+// modelFields son los campos que todo registro guarda (ver populateModel en
+// los handlers): el esquema debe declararlos como cadenas requeridas.
+var modelFields = []string{"nombre", "apellido_paterno", "email", "sexo"}
+
+// checkModelFields verifica que el esquema garantice los campos que el modelo
+// del participante necesita. 'categoria' puede ser opcional si el evento la
+// asigna por edad.
+func (b *builder) checkModelFields() {
+	for _, name := range modelFields {
+		if f, ok := b.schema.byName[name]; !ok || f.Type != String || !f.Required {
+			b.modelError(name, diagnostic.SchModelField, name)
+		}
+	}
+	f, ok := b.schema.byName["categoria"]
+	assigned := b.schema.AutoCategory && len(b.schema.Categories) > 0
+	if !ok || f.Type != String || !f.Required && !assigned {
+		b.modelError("categoria", diagnostic.SchModelCategory)
+	}
+}
+
+// modelError reporta el error sobre el bloque del campo, o sin ubicación si
+// el campo no está declarado.
+func (b *builder) modelError(name string, code diagnostic.Code, args ...interface{}) {
+	if block, ok := b.fields[name]; ok {
+		b.addError(block, code, args...)
+		return
+	}
+	b.errors = append(b.errors, diagnostic.New(code, token.Span{}, args...))
+}
+
+func (b *builder) reader(block *ast.Block) *blockReader {
+	data, spans := parser.DataFromStatements(block.Statements)
+	return &blockReader{b: b, block: block, data: data, spans: spans, used: make(map[string]bool)}
+}
+
+// blockReader lee las claves de un bloque con el tipo esperado y, al
+// terminar, reporta las claves que sobran.
+type blockReader struct {
+	b     *builder
+	block *ast.Block
+	data  parser.ParticipantData
+	spans parser.Spans
+	used  map[string]bool
+}
+
+// addError reporta un error sobre el valor de key, o sobre el bloque si la
+// clave no está presente.
+func (r *blockReader) addError(key string, code diagnostic.Code, args ...interface{}) {
+	span, ok := r.spans[key]
+	if !ok {
+		span = r.block.Token.Span
+	}
+	r.b.errors = append(r.b.errors, diagnostic.New(code, span, args...))
+}
+
+// inapplicable reporta las claves presentes que no tienen sentido para el tipo t.
+func (r *blockReader) inapplicable(t Type, keys ...string) {
+	for _, key := range keys {
+		if _, present := r.data[key]; present {
+			r.addError(key, diagnostic.SchInapplicable, key, t)
+		}
+	}
+}
+
+func (r *blockReader) value(key string) interface{} {
+	r.used[key] = true
+	return r.data[key]
+}
+
+func (r *blockReader) str(key string, required bool) string {
+	v, present := r.data[key]
+	r.used[key] = true
+	if !present {
+		if required {
+			r.addError(key, diagnostic.SchMissingKey, key, r.block.Token.Literal)
+		}
+		return ""
+	}
+	s, ok := v.(string)
+	if !ok {
+		r.addError(key, diagnostic.SchWrongKeyType, key, String.Description())
+	}
+	return s
+}
+
+func (r *blockReader) boolean(key string) bool {
+	v, present := r.data[key]
+	r.used[key] = true
+	if !present {
+		return false
+	}
+	value, ok := v.(bool)
+	if !ok {
+		r.addError(key, diagnostic.SchWrongKeyType, key, Bool.Description())
+	}
+	return value
+}
+
+func (r *blockReader) number(key string) *float64 {
+	v, present := r.data[key]
+	r.used[key] = true
+	if !present {
+		return nil
+	}
+	var n float64
+	switch value := v.(type) {
+	case int64:
+		n = float64(value)
+	case float64:
+		n = value
+	default:
+		r.addError(key, diagnostic.SchWrongKeyType, key, Float.Description())
+		return nil
+	}
+	return &n
+}
+
+func (r *blockReader) date(key string) time.Time {
+	v, present := r.data[key]
+	r.used[key] = true
+	if !present {
+		return time.Time{}
+	}
+	value, ok := v.(time.Time)
+	if !ok {
+		r.addError(key, diagnostic.SchWrongKeyType, key, Date.Description())
+	}
+	return value
+}
+
+// age lee una edad en años; 0 si la clave no está.
+func (r *blockReader) age(key string) int {
+	v, present := r.data[key]
+	r.used[key] = true
+	if !present {
+		return 0
+	}
+	value, ok := v.(int64)
+	if !ok || value < 0 {
+		r.addError(key, diagnostic.SchWrongKeyType, key, Int.Description())
+		return 0
+	}
+	return int(value)
+}
+
+func (r *blockReader) strs(key string) []string {
+	v, present := r.data[key]
+	r.used[key] = true
+	if !present {
+		return nil
+	}
+	if !StringList.Accepts(v) {
+		r.addError(key, diagnostic.SchWrongKeyType, key, StringList.Description())
+		return nil
+	}
+	items := v.([]interface{})
+	values := make([]string, len(items))
+	for i, item := range items {
+		values[i] = item.(string)
+	}
+	return values
+}
+
+// finish reporta las claves del bloque que ningún lector consumió.
+func (r *blockReader) finish() {
+	for _, stmt := range r.block.Statements {
+		if !r.used[stmt.Key.Name] {
+			r.b.errors = append(r.b.errors, diagnostic.New(diagnostic.SchUnknownKey, stmt.Key.Span(), stmt.Key.Name, r.block.Token.Literal))
+		}
+	}
+}
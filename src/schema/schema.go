@@ -0,0 +1,156 @@
+package schema
+
+import (
+	"compilerciclista/src/ast"
+	"compilerciclista/src/category"
+	"compilerciclista/src/diagnostic"
+	"compilerciclista/src/emailcheck"
+	"compilerciclista/src/lexer"
+	"compilerciclista/src/parser"
+	_ "embed"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Schema declara los campos y reglas con los que se valida el registro de un
+// evento. Se escribe en el mismo DSL que los registros, con bloques 'evento',
+// 'campo', 'regla' y 'categoria' (ver default.ciclista).
+type Schema struct {
+	Event  string
+	Fields []*Field
+	Rules  []*Rule
+
+	// RaceDate es el día de la carrera, con el que se calcula la edad de
+	// cada participante; si es cero se usa el día del registro.
+	RaceDate time.Time
+	// Categories son las categorías por edad y sexo; si está vacía no se
+	// verifica la categoría elegida.
+	Categories category.Table
+	// AutoCategory permite omitir 'categoria' y asignarla a partir de la
+	// fecha de nacimiento.
+	AutoCategory bool
+
+	byName map[string]*Field
+}
+
+// Field describe un campo aceptado en los documentos de registro.
+type Field struct {
+	Name     string
+	Type     Type
+	Required bool
+	Values   []string       // valores permitidos; vacío si no hay restricción
+	Pattern  *regexp.Regexp // expresión que debe cumplir una cadena
+	Message  string         // explicación cuando no se cumple Pattern
+	Min, Max *float64       // límites para enteros y decimales
+	Format   string         // formato especial de una cadena, como FormatEmail
+	Email    *emailcheck.Policy
+}
+
+// Formatos especiales de las cadenas.
+const (
+	// FormatEmail marca una dirección de correo. Sus dominios se configuran
+	// con las claves dominios_permitidos, dominios_bloqueados,
+	// bloquear_desechables y verificar_mx.
+	FormatEmail = "email"
+	// FormatCURP marca una CURP, que además debe coincidir con la fecha de
+	// nacimiento y el sexo del documento.
+	FormatCURP = "curp"
+)
+
+// Rule es una regla entre campos: si el campo If tiene el valor Equals (o
+// simplemente existe, cuando Equals es nil), el campo Requires no puede faltar
+// ni estar vacío.
+type Rule struct {
+	If       string
+	Equals   interface{}
+	Requires string
+	Message  string
+}
+
+//go:embed default.ciclista
+var defaultSource string
+
+var defaultSchema = MustParse(defaultSource)
+
+// Default devuelve el esquema incluido en el binario.
+func Default() *Schema {
+	return defaultSchema
+}
+
+// Field busca un campo por nombre.
+func (s *Schema) Field(name string) (*Field, bool) {
+	f, ok := s.byName[name]
+	return f, ok
+}
+
+// FieldNames devuelve los nombres de los campos en el orden del esquema.
+func (s *Schema) FieldNames() []string {
+	names := make([]string, len(s.Fields))
+	for i, f := range s.Fields {
+		names[i] = f.Name
+	}
+	return names
+}
+
+// Load lee y valida el esquema guardado en path.
+func Load(path string) (*Schema, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo leer el esquema: %w", err)
+	}
+
+	s, errors := Parse(string(content))
+	if len(errors) > 0 {
+		lines := make([]string, len(errors))
+		for i, d := range errors {
+			lines[i] = fmt.Sprintf("%s:%d:%d: %s", path, d.Span.Start.Line, d.Span.Start.Column, d.Message)
+		}
+		return nil, fmt.Errorf("el esquema tiene errores:\n%s", strings.Join(lines, "\n"))
+	}
+	return s, nil
+}
+
+// MustParse es como Parse pero termina el programa si el esquema no es válido.
+// Se usa para el esquema incluido en el binario.
+func MustParse(input string) *Schema {
+	s, errors := Parse(input)
+	if len(errors) > 0 {
+		panic(fmt.Sprintf("schema: esquema inválido: %s", errors[0]))
+	}
+	return s
+}
+
+// Parse construye un esquema a partir de su texto.
+func Parse(input string) (*Schema, []diagnostic.Diagnostic) {
+	p := parser.New(lexer.New(input))
+	doc, errors := p.ParseBlocks()
+	if len(errors) > 0 {
+		return nil, errors
+	}
+
+	b := &builder{schema: &Schema{byName: make(map[string]*Field)}, fields: make(map[string]*ast.Block)}
+	for _, block := range doc.Blocks {
+		switch block.Token.Literal {
+		case "evento":
+			b.event(block)
+		case "campo":
+			b.field(block)
+		case "regla":
+			b.rule(block)
+		case "categoria":
+			b.category(block)
+		default:
+			b.addError(block, diagnostic.SchUnknownBlock, block.Token.Literal)
+		}
+	}
+	b.checkRules()
+	b.checkModelFields()
+
+	if len(b.errors) > 0 {
+		return nil, b.errors
+	}
+	return b.schema, nil
+}
@@ -0,0 +1,68 @@
+package schema
+
+import (
+	"compilerciclista/src/diagnostic"
+	"strings"
+	"testing"
+)
+
+// withField reemplaza el bloque del campo name del esquema por defecto.
+func withField(name, block string) string {
+	start := strings.Index(defaultSource, "campo {\n    nombre: \""+name+"\";")
+	end := start + strings.Index(defaultSource[start:], "}\n") + 2
+	return defaultSource[:start] + block + defaultSource[end:]
+}
+
+func TestParseDefault(t *testing.T) {
+	if _, errors := Parse(defaultSource); len(errors) > 0 {
+		t.Fatalf("el esquema por defecto no es válido: %v", errors)
+	}
+}
+
+func TestParseModelFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   diagnostic.Code // vacío si el esquema es válido
+	}{
+		{
+			name:   "email opcional",
+			source: withField("email", "campo {\n    nombre: \"email\";\n    tipo: \"cadena\";\n}\n"),
+			want:   diagnostic.SchModelField,
+		},
+		{
+			name:   "sexo no declarado",
+			source: withField("sexo", ""),
+			want:   diagnostic.SchModelField,
+		},
+		{
+			name:   "nombre de otro tipo",
+			source: withField("nombre", "campo {\n    nombre: \"nombre\";\n    tipo: \"entero\";\n    requerido: true;\n}\n"),
+			want:   diagnostic.SchModelField,
+		},
+		{
+			name:   "categoría opcional sin asignación por edad",
+			source: strings.Replace(defaultSource, "asignar_categoria: true;", "", 1),
+			want:   diagnostic.SchModelCategory,
+		},
+		{
+			name:   "categoría requerida sin asignación por edad",
+			source: withField("categoria", "campo {\n    nombre: \"categoria\";\n    tipo: \"cadena\";\n    requerido: true;\n}\n"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errors := Parse(tt.source)
+			if tt.want == "" {
+				if len(errors) > 0 {
+					t.Fatalf("se esperaba un esquema válido, se obtuvo %v", errors)
+				}
+				return
+			}
+			if len(errors) != 1 || errors[0].Code != tt.want {
+				t.Fatalf("se esperaba un error %s, se obtuvo %v", tt.want, errors)
+			}
+		})
+	}
+}
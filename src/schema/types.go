@@ -0,0 +1,84 @@
+package schema
+
+import (
+	"compilerciclista/src/diagnostic"
+	"time"
+)
+
+// Type es el tipo de un campo tal como se escribe en el esquema.
+type Type string
+
+const (
+	String     Type = "cadena"
+	Bool       Type = "booleano"
+	Int        Type = "entero"
+	Float      Type = "decimal"
+	Date       Type = "fecha"
+	StringList Type = "lista" // lista de cadenas
+)
+
+var typeDescriptions = map[Type]diagnostic.Phrase{
+	String:     {ES: "una cadena de texto", EN: "a text string"},
+	Bool:       {ES: "un booleano", EN: "a boolean"},
+	Int:        {ES: "un número entero", EN: "an integer"},
+	Float:      {ES: "un número decimal", EN: "a decimal number"},
+	Date:       {ES: "una fecha (AAAA-MM-DD)", EN: "a date (YYYY-MM-DD)"},
+	StringList: {ES: "una lista de cadenas de texto", EN: "a list of text strings"},
+}
+
+// IsValid indica si t es uno de los tipos que reconoce el esquema.
+func (t Type) IsValid() bool {
+	_, ok := typeDescriptions[t]
+	return ok
+}
+
+// Description devuelve el nombre del tipo para los mensajes de error, en
+// todos los idiomas del catálogo de diagnósticos.
+func (t Type) Description() diagnostic.Phrase {
+	return typeDescriptions[t]
+}
+
+// Accepts indica si un valor producido por el parser es de este tipo.
+// Un entero es aceptable donde se espera un decimal (peso: 70;).
+func (t Type) Accepts(value interface{}) bool {
+	actual, ok := TypeOf(value)
+	return ok && (actual == t || t == Float && actual == Int)
+}
+
+// TypeOf devuelve el tipo de un valor producido por el parser. El segundo
+// resultado es false si el valor no corresponde a ningún tipo del esquema
+// (por ejemplo, una lista que contiene números).
+func TypeOf(value interface{}) (Type, bool) {
+	switch v := value.(type) {
+	case string:
+		return String, true
+	case bool:
+		return Bool, true
+	case int64:
+		return Int, true
+	case float64:
+		return Float, true
+	case time.Time:
+		return Date, true
+	case []interface{}:
+		for _, item := range v {
+			if _, ok := item.(string); !ok {
+				return "", false
+			}
+		}
+		return StringList, true
+	default:
+		return "", false
+	}
+}
+
+// Describe devuelve la descripción del tipo de un valor para los mensajes.
+func Describe(value interface{}) diagnostic.Phrase {
+	if t, ok := TypeOf(value); ok {
+		return t.Description()
+	}
+	if _, ok := value.([]interface{}); ok {
+		return diagnostic.Phrase{ES: "una lista", EN: "a list"}
+	}
+	return diagnostic.Phrase{ES: "un valor desconocido", EN: "an unknown value"}
+}
@@ -1,60 +1,308 @@
 package semantic
 
 import (
+	"compilerciclista/src/category"
+	"compilerciclista/src/curp"
+	"compilerciclista/src/diagnostic"
+	"compilerciclista/src/emailcheck"
 	"compilerciclista/src/parser"
-	"fmt"
+	"compilerciclista/src/schema"
+	"compilerciclista/src/token"
+	"reflect"
+	"sort"
 	"strings"
+	"time"
 )
 
 type ParticipantData map[string]interface{}
 
-func Analyze(data parser.ParticipantData) []string {
-	var errors []string
+// active es el esquema con el que Analyze valida los documentos.
+var active = schema.Default()
 
-	// 1. Validar campos requeridos
-	requiredFields := []string{"nombre", "apellido_paterno", "email", "sexo", "categoria"}
-	for _, field := range requiredFields {
-		if _, ok := data[field]; !ok {
-			errors = append(errors, fmt.Sprintf("Error semántico: el campo requerido '%s' no fue encontrado.", field))
+// UseSchema reemplaza el esquema de validación, normalmente al iniciar el
+// servidor con el archivo del evento.
+func UseSchema(s *schema.Schema) {
+	active = s
+}
+
+// ActiveSchema devuelve el esquema con el que se valida actualmente.
+func ActiveSchema() *schema.Schema {
+	return active
+}
+
+// Analyze valida el documento parseado con el esquema activo. spans permite
+// ubicar cada error en el valor que lo provocó; los campos ausentes se
+// reportan sin posición.
+// Devuelve errores, advertencias e información; solo los errores impiden el
+// registro. Si el esquema asigna categorías, la asignada se agrega a data.
+func Analyze(data parser.ParticipantData, spans parser.Spans) []diagnostic.Diagnostic {
+	return AnalyzeWith(active, data, spans)
+}
+
+// AnalyzeWith valida el documento con el esquema indicado.
+func AnalyzeWith(s *schema.Schema, data parser.ParticipantData, spans parser.Spans) []diagnostic.Diagnostic {
+	var errors []diagnostic.Diagnostic
+	fields := fieldsInOrder(data, spans)
+
+	// 1. Advertir sobre campos desconocidos, sugiriendo el más parecido.
+	// typos guarda, por cada campo sugerido, la clave mal escrita que lo originó.
+	typos := make(map[string]string)
+	for _, field := range fields {
+		if _, known := s.Field(field); known {
+			continue
+		}
+		if suggestion, ok := suggestField(field, s.FieldNames()); ok {
+			typos[suggestion] = field
+			errors = append(errors, diagnostic.New(diagnostic.SemUnknownFieldHint, spans[field], field, suggestion).WithField(field))
+		} else {
+			errors = append(errors, diagnostic.New(diagnostic.SemUnknownField, spans[field], field).WithField(field))
 		}
 	}
-	
-	if len(errors) > 0 {
+
+	// 2. Validar campos requeridos
+	for _, f := range s.Fields {
+		if _, ok := data[f.Name]; ok || !f.Required {
+			continue
+		}
+		if typo, ok := typos[f.Name]; ok {
+			errors = append(errors, diagnostic.New(diagnostic.SemMissingFieldHint, spans[typo], f.Name, typo).WithField(f.Name))
+		} else {
+			errors = append(errors, diagnostic.New(diagnostic.SemMissingField, spans[f.Name], f.Name).WithField(f.Name))
+		}
+	}
+
+	if diagnostic.HasErrors(errors) {
 		return errors
 	}
 
-	// 2. Validar formato de email (debe ser de Gmail)
-	email, ok := data["email"].(string)
-	if !ok {
-		errors = append(errors, "Error semántico: el campo 'email' debe ser una cadena de texto.")
-	} else if !strings.HasSuffix(email, "@gmail.com") {
-		errors = append(errors, "Error semántico: el correo electrónico debe ser de Gmail.")
+	// 3. Validar el tipo y las restricciones de cada campo conocido
+	for _, name := range fields {
+		f, known := s.Field(name)
+		if !known {
+			continue
+		}
+		value := data[name]
+		if !f.Type.Accepts(value) {
+			errors = append(errors, diagnostic.New(diagnostic.SemWrongType, spans[name], name, f.Type.Description(), schema.Describe(value)).WithField(name))
+			continue
+		}
+		if d, failed := checkConstraints(f, value, spans[name]); failed {
+			errors = append(errors, d.WithField(name))
+		}
 	}
 
-	// 3. Validar el valor de 'sexo'
-	sexo, ok := data["sexo"].(string)
-	if !ok {
-		errors = append(errors, "Error semántico: el campo 'sexo' debe ser una cadena de texto.")
-	} else if sexo != "M" && sexo != "F" {
-		errors = append(errors, "Error semántico: el valor de 'sexo' debe ser 'M' o 'F'.")
+	// 4. Verificar que la CURP coincida con los demás datos, y la categoría
+	// elegida o asignarla por la edad
+	if !diagnostic.HasErrors(errors) {
+		errors = append(errors, checkCURPs(s, data, spans)...)
+	}
+	if !diagnostic.HasErrors(errors) {
+		errors = append(errors, checkCategory(s, data, spans)...)
 	}
 
-	// 4. Validar la categoría
-	validCategories := map[string]bool{"Elite": true, "Aficionado": true, "Juvenil": true}
-	categoria, ok := data["categoria"].(string)
-	if !ok {
-		errors = append(errors, "Error semántico: el campo 'categoria' debe ser una cadena de texto.")
-	} else if !validCategories[categoria] {
-		errors = append(errors, fmt.Sprintf("Error semántico: la categoría '%s' no es válida.", categoria))
+	// 5. Validar las reglas entre campos
+	for _, rule := range s.Rules {
+		value, present := data[rule.If]
+		if !present || rule.Equals != nil && !reflect.DeepEqual(value, rule.Equals) {
+			continue
+		}
+		if !isEmpty(data[rule.Requires]) {
+			continue
+		}
+		d := diagnostic.New(diagnostic.SemRuleViolated, spans[rule.If], rule.If, value, rule.Requires)
+		if rule.Message != "" {
+			d = diagnostic.Custom(diagnostic.SemRuleViolated, spans[rule.If], customMessage(rule.Message))
+		}
+		errors = append(errors, d.WithField(rule.Requires))
 	}
-	
-	// 5. Validar consistencia de pago
-    pago, pagoExists := data["pago_realizado"].(bool)
-    comprobante, comprobanteExists := data["comprobante_pago_path"].(string)
-    
-    if pagoExists && pago && (!comprobanteExists || comprobante == "") {
-        errors = append(errors, "Error semántico: si 'pago_realizado' es true, 'comprobante_pago_path' no puede estar vacío.")
-    }
 
 	return errors
-}
\ No newline at end of file
+}
+
+// checkConstraints aplica los valores permitidos, el patrón y los límites
+// numéricos de un campo. El segundo resultado indica si se encontró un problema.
+func checkConstraints(f *schema.Field, value interface{}, span token.Span) (diagnostic.Diagnostic, bool) {
+	if text, ok := value.(string); ok {
+		if len(f.Values) > 0 && !contains(f.Values, text) {
+			return diagnostic.New(diagnostic.SemValueNotAllowed, span, text, f.Name, strings.Join(f.Values, ", ")), true
+		}
+		if f.Pattern != nil && !f.Pattern.MatchString(text) {
+			if f.Message != "" {
+				return diagnostic.Custom(diagnostic.SemPatternMismatch, span, customMessage(f.Message)), true
+			}
+			return diagnostic.New(diagnostic.SemPatternMismatch, span, f.Name), true
+		}
+		if f.Email != nil {
+			return checkEmail(f.Email, text, span)
+		}
+		if f.Format == schema.FormatCURP {
+			switch _, problem := curp.Parse(text); problem {
+			case curp.OK:
+			case curp.InvalidCheckDigit:
+				return diagnostic.New(diagnostic.SemCURPCheckDigit, span, text), true
+			default:
+				return diagnostic.New(diagnostic.SemInvalidCURP, span, text), true
+			}
+		}
+	}
+
+	if n, ok := toFloat(value); ok {
+		if f.Min != nil && n < *f.Min {
+			return diagnostic.New(diagnostic.SemBelowMinimum, span, f.Name, *f.Min), true
+		}
+		if f.Max != nil && n > *f.Max {
+			return diagnostic.New(diagnostic.SemAboveMaximum, span, f.Name, *f.Max), true
+		}
+	}
+	return diagnostic.Diagnostic{}, false
+}
+
+// Campos que usa la asignación de categorías.
+const (
+	categoryField = "categoria"
+	birthField    = "fecha_nacimiento"
+	sexoField     = "sexo"
+)
+
+// checkCategory compara la categoría del documento con la que corresponde a
+// la edad el día de la carrera y al sexo. Si el documento no trae categoría y
+// el esquema lo permite, la asigna en data. Una categoría por edad elegida sin
+// fecha de nacimiento no se puede verificar y es un error.
+func checkCategory(s *schema.Schema, data parser.ParticipantData, spans parser.Spans) []diagnostic.Diagnostic {
+	if len(s.Categories) == 0 {
+		return nil
+	}
+
+	chosen, hasChosen := data[categoryField].(string)
+	if hasChosen && s.Categories.IsOpen(chosen) {
+		return nil // Las categorías abiertas no dependen de la edad
+	}
+	birth, hasBirth := data[birthField].(time.Time)
+	if !hasBirth {
+		switch {
+		case !hasChosen && s.AutoCategory:
+			// Sin categoría, la fecha de nacimiento es necesaria para asignarla
+			return []diagnostic.Diagnostic{diagnostic.New(diagnostic.SemMissingField, token.Span{}, birthField).WithField(birthField)}
+		case hasChosen && s.Categories.DependsOnAge(chosen):
+			return []diagnostic.Diagnostic{diagnostic.New(diagnostic.SemCategoryNoBirth, spans[categoryField], chosen).WithField(birthField)}
+		}
+		return nil
+	}
+
+	raceDay := s.RaceDate
+	if raceDay.IsZero() {
+		raceDay = time.Now()
+	}
+	if birth.After(raceDay) {
+		return []diagnostic.Diagnostic{diagnostic.New(diagnostic.SemBornAfterRace, spans[birthField]).WithField(birthField)}
+	}
+
+	age := category.AgeOn(birth, raceDay)
+	sexo, _ := data[sexoField].(string)
+	expected, ok := s.Categories.Assign(age, sexo)
+	switch {
+	case !ok:
+		return []diagnostic.Diagnostic{diagnostic.New(diagnostic.SemNoCategory, spans[birthField], age, sexo).WithField(birthField)}
+	case !hasChosen && s.AutoCategory:
+		data[categoryField] = expected.Name
+		return []diagnostic.Diagnostic{diagnostic.New(diagnostic.SemCategoryAssigned, spans[birthField], expected.Name, age).WithField(categoryField)}
+	case hasChosen && chosen != expected.Name:
+		return []diagnostic.Diagnostic{diagnostic.New(diagnostic.SemCategoryMismatch, spans[categoryField], chosen, age, expected.Name).WithField(categoryField)}
+	}
+	return nil
+}
+
+// checkCURPs compara la fecha de nacimiento y el sexo codificados en cada
+// campo con formato CURP contra los campos correspondientes del documento.
+func checkCURPs(s *schema.Schema, data parser.ParticipantData, spans parser.Spans) []diagnostic.Diagnostic {
+	var errors []diagnostic.Diagnostic
+	for _, f := range s.Fields {
+		text, ok := data[f.Name].(string)
+		if f.Format != schema.FormatCURP || !ok {
+			continue
+		}
+		key, problem := curp.Parse(text)
+		if problem != curp.OK {
+			continue
+		}
+		if birth, ok := data[birthField].(time.Time); ok && !birth.Equal(key.BirthDate) {
+			errors = append(errors, diagnostic.New(diagnostic.SemCURPBirthDate, spans[f.Name],
+				key.BirthDate.Format(parser.DateLayout), birthField, birth.Format(parser.DateLayout)).WithField(f.Name))
+		}
+		if sexo, ok := data[sexoField].(string); ok && key.Sexo() != "" && sexo != key.Sexo() {
+			errors = append(errors, diagnostic.New(diagnostic.SemCURPSexo, spans[f.Name], key.Sexo(), sexoField, sexo).WithField(f.Name))
+		}
+	}
+	return errors
+}
+
+// checkEmail valida la sintaxis y el dominio de una dirección de correo.
+func checkEmail(policy *emailcheck.Policy, address string, span token.Span) (diagnostic.Diagnostic, bool) {
+	switch problem, domain := policy.Check(address); problem {
+	case emailcheck.InvalidSyntax:
+		return diagnostic.New(diagnostic.SemInvalidEmail, span, address), true
+	case emailcheck.DomainNotAllowed:
+		return diagnostic.New(diagnostic.SemEmailNotAllowed, span, domain, strings.Join(policy.Allowed, ", ")), true
+	case emailcheck.DomainDenied:
+		return diagnostic.New(diagnostic.SemEmailDenied, span, domain), true
+	case emailcheck.DisposableDomain:
+		return diagnostic.New(diagnostic.SemEmailDisposable, span, domain), true
+	case emailcheck.NoMailServer:
+		return diagnostic.New(diagnostic.SemEmailNoMX, span, domain), true
+	}
+	return diagnostic.Diagnostic{}, false
+}
+
+// customMessage da a un mensaje escrito en el esquema la misma forma que los
+// del catálogo.
+func customMessage(message string) string {
+	return "Error semántico: " + message + "."
+}
+
+// isEmpty indica si un valor falta o no tiene contenido.
+func isEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// toFloat acepta tanto enteros como decimales del DSL.
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// fieldsInOrder devuelve las claves del documento en el orden en que aparecen,
+// para que los diagnósticos sigan el orden del texto.
+func fieldsInOrder(data parser.ParticipantData, spans parser.Spans) []string {
+	fields := make([]string, 0, len(data))
+	for field := range data {
+		fields = append(fields, field)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return spans[fields[i]].Start.Offset < spans[fields[j]].Start.Offset
+	})
+	return fields
+}
@@ -0,0 +1,114 @@
+package semantic
+
+import (
+	"compilerciclista/src/diagnostic"
+	"compilerciclista/src/parser"
+	"compilerciclista/src/schema"
+	"testing"
+	"time"
+)
+
+// raceSchema es el esquema por defecto con la carrera el 10 de mayo de 2026.
+func raceSchema() *schema.Schema {
+	s := *schema.Default()
+	s.RaceDate = time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC)
+	return &s
+}
+
+func birth(value string) time.Time {
+	t, err := time.Parse(parser.DateLayout, value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestCheckCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     parser.ParticipantData
+		code     diagnostic.Code // vacío si no hay diagnóstico
+		assigned string          // categoría que queda en data
+	}{
+		{
+			name:     "categoría correcta",
+			data:     parser.ParticipantData{"sexo": "M", "fecha_nacimiento": birth("2000-01-01"), "categoria": "Elite"},
+			assigned: "Elite",
+		},
+		{
+			name:     "categoría que no corresponde",
+			data:     parser.ParticipantData{"sexo": "M", "fecha_nacimiento": birth("2000-01-01"), "categoria": "Juvenil"},
+			code:     diagnostic.SemCategoryMismatch,
+			assigned: "Juvenil",
+		},
+		{
+			name:     "cumple años después de la carrera",
+			data:     parser.ParticipantData{"sexo": "F", "fecha_nacimiento": birth("2003-05-11"), "categoria": "Sub-23"},
+			assigned: "Sub-23",
+		},
+		{
+			name:     "asignada por edad",
+			data:     parser.ParticipantData{"sexo": "F", "fecha_nacimiento": birth("1980-01-01")},
+			code:     diagnostic.SemCategoryAssigned,
+			assigned: "Master B",
+		},
+		{
+			name: "sin categoría ni fecha de nacimiento",
+			data: parser.ParticipantData{"sexo": "F"},
+			code: diagnostic.SemMissingField,
+		},
+		{
+			name:     "categoría por edad sin fecha de nacimiento",
+			data:     parser.ParticipantData{"sexo": "M", "categoria": "Elite"},
+			code:     diagnostic.SemCategoryNoBirth,
+			assigned: "Elite",
+		},
+		{
+			name:     "categoría abierta sin fecha de nacimiento",
+			data:     parser.ParticipantData{"sexo": "M", "categoria": "Aficionado"},
+			assigned: "Aficionado",
+		},
+		{
+			name:     "categoría abierta a cualquier edad",
+			data:     parser.ParticipantData{"sexo": "M", "fecha_nacimiento": birth("1950-01-01"), "categoria": "Aficionado"},
+			assigned: "Aficionado",
+		},
+		{
+			name: "nacido después de la carrera",
+			data: parser.ParticipantData{"sexo": "M", "fecha_nacimiento": birth("2026-06-01")},
+			code: diagnostic.SemBornAfterRace,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnostics := checkCategory(raceSchema(), tt.data, parser.Spans{})
+			switch {
+			case tt.code == "" && len(diagnostics) > 0:
+				t.Errorf("no se esperaban diagnósticos, se obtuvo %v", diagnostics)
+			case tt.code != "" && (len(diagnostics) != 1 || diagnostics[0].Code != tt.code):
+				t.Errorf("se esperaba %s, se obtuvo %v", tt.code, diagnostics)
+			}
+			if got, _ := tt.data["categoria"].(string); got != tt.assigned {
+				t.Errorf("categoria = %q, se esperaba %q", got, tt.assigned)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRejectsAgeCategoryWithoutBirth(t *testing.T) {
+	data := parser.ParticipantData{
+		"nombre":           "Ana",
+		"apellido_paterno": "López",
+		"email":            "ana@unam.mx",
+		"sexo":             "F",
+		"categoria":        "Elite",
+	}
+	diagnostics := AnalyzeWith(raceSchema(), data, parser.Spans{})
+	if !diagnostic.HasErrors(diagnostics) {
+		t.Fatalf("se aceptó una categoría por edad sin fecha de nacimiento: %v", diagnostics)
+	}
+	if diagnostics[0].Field != "fecha_nacimiento" {
+		t.Errorf("el error señala el campo %q, se esperaba 'fecha_nacimiento'", diagnostics[0].Field)
+	}
+}
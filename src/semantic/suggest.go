@@ -0,0 +1,47 @@
+package semantic
+
+// suggestField busca, entre los campos conocidos, el más parecido a name.
+// Solo sugiere cuando la distancia de edición es pequeña en relación con la
+// longitud del nombre, para no proponer campos sin relación. Ante un empate
+// gana el primero en el orden de known.
+func suggestField(name string, known []string) (string, bool) {
+	best, bestDistance := "", -1
+	for _, field := range known {
+		d := editDistance(name, field)
+		if bestDistance < 0 || d < bestDistance {
+			best, bestDistance = field, d
+		}
+	}
+
+	maxDistance := len([]rune(name)) / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+	if maxDistance > 3 {
+		maxDistance = 3
+	}
+	return best, bestDistance >= 0 && bestDistance <= maxDistance
+}
+
+// editDistance calcula la distancia de Levenshtein entre a y b, por runas.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -7,23 +7,40 @@ import (
 )
 
 // GenerateParticipantCode crea un código único para un nuevo participante.
-func GenerateParticipantCode(categoria string) (string, error) {
-	// 1. Obtener el prefijo de la categoría (ej: "JUV", "ELI", "AFI")
-	prefix := "GEN" // Prefijo genérico por si acaso
-	if len(categoria) >= 3 {
-		prefix = strings.ToUpper(categoria[0:3])
-	}
+// Conviene llamarla con el repositorio de la transacción que lo inserta, para
+// que el número reservado se libere si la inserción falla. La secuencia nunca
+// retrocede, así que el código de una inscripción dada de baja no se vuelve a
+// emitir: sigue siendo suyo si se reactiva.
+func GenerateParticipantCode(repo database.ParticipantRepository, evento, categoria string) (string, error) {
+	// 1. Obtener el prefijo de la categoría (ej: "JUV", "ELI", "MASA")
+	prefix := categoryPrefix(categoria)
 
-	// 2. Contar los participantes existentes para obtener el siguiente número
-	count, err := database.CountParticipants()
+	// 2. Reservar el siguiente número de la secuencia de la categoría en el evento
+	nextNumber, err := repo.NextSequence(evento, prefix)
 	if err != nil {
-		return "", fmt.Errorf("no se pudo obtener el conteo de participantes: %w", err)
+		return "", fmt.Errorf("no se pudo reservar el número de participante: %w", err)
 	}
-	nextNumber := count + 1
 
 	// 3. Formatear el código final (ej: "JUV-001")
 	// %03d significa: formatea como un entero, con 3 dígitos, rellenando con ceros a la izquierda.
 	participantCode := fmt.Sprintf("%s-%03d", prefix, nextNumber)
 
 	return participantCode, nil
-}
\ No newline at end of file
+}
+
+// categoryPrefix toma las tres primeras letras de la categoría (o todas, si
+// la primera palabra es más corta) y la inicial de cada palabra siguiente,
+// para que "Master A" y "Master B" no compartan prefijo (ni secuencia):
+// "MASA", "MASB". Cuenta letras y no bytes, así que "Ñiños" da "ÑIÑ".
+func categoryPrefix(categoria string) string {
+	words := strings.Fields(categoria)
+	if len(words) == 0 {
+		return "GEN" // Prefijo genérico por si acaso
+	}
+	first := []rune(words[0])
+	prefix := string(first[:min(3, len(first))])
+	for _, word := range words[1:] {
+		prefix += string([]rune(word)[0])
+	}
+	return strings.ToUpper(prefix)
+}
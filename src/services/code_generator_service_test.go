@@ -0,0 +1,28 @@
+package services
+
+import "testing"
+
+func TestCategoryPrefix(t *testing.T) {
+	tests := []struct {
+		categoria string
+		want      string
+	}{
+		{"Elite", "ELI"},
+		{"Master A", "MASA"},
+		{"Master B", "MASB"},
+		{"Sub-23", "SUB"},
+		{"Élite", "ÉLI"},
+		{"Ñiños", "ÑIÑ"},
+		{"Máster Ñ", "MÁSÑ"},
+		{"MX", "MX"},
+		{"Ah élite", "AHÉ"},
+		{"  ", "GEN"},
+		{"", "GEN"},
+	}
+
+	for _, tt := range tests {
+		if got := categoryPrefix(tt.categoria); got != tt.want {
+			t.Errorf("categoryPrefix(%q) = %q, se esperaba %q", tt.categoria, got, tt.want)
+		}
+	}
+}
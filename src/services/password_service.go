@@ -0,0 +1,28 @@
+package services
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MinPasswordLength es la longitud mínima de las contraseñas de las cuentas.
+const MinPasswordLength = 8
+
+// HashPassword cifra la contraseña con bcrypt para guardarla.
+func HashPassword(password string) (string, error) {
+	if utf8.RuneCountInString(password) < MinPasswordLength {
+		return "", fmt.Errorf("la contraseña debe tener al menos %d caracteres", MinPasswordLength)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("no se pudo cifrar la contraseña: %w", err)
+	}
+	return string(hash), nil
+}
+
+// CheckPassword indica si password corresponde al hash guardado.
+func CheckPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
@@ -9,8 +9,77 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 )
 
+// Audiencias de los tokens: los corredores los usan en las rutas de
+// autoservicio y el personal del evento en las de administración.
+const (
+	ParticipantAudience = "participantes"
+	StaffAudience       = "personal"
+)
+
+// defaultIssuer firma los tokens cuando JWT_ISSUER no está configurado.
+const defaultIssuer = "compilerciclista"
+
+// Vigencia de los tokens: la del participante cubre hasta la carrera; la del
+// personal, una jornada.
+const (
+	tokenLifetime      = 72 * time.Hour
+	staffTokenLifetime = 12 * time.Hour
+)
+
+// Claims son los datos que lleva un token. Los de participante identifican su
+// inscripción; los de cuenta llevan el usuario en sub y su rol.
+type Claims struct {
+	ParticipantID   int64  `json:"participant_id,omitempty"`
+	ParticipantCode string `json:"participant_code,omitempty"`
+	Evento          string `json:"evento,omitempty"`
+	Email           string `json:"email,omitempty"`
+	Nombre          string `json:"nombre,omitempty"`
+	Rol             string `json:"rol"`
+	jwt.RegisteredClaims
+}
+
 // GenerateToken crea un nuevo token JWT para un participante.
 func GenerateToken(participant models.Participant) (string, error) {
+	now := time.Now()
+	return signToken(Claims{
+		ParticipantID:   participant.ID,
+		ParticipantCode: participant.ParticipantCode,
+		Evento:          participant.Evento,
+		Email:           participant.Email,
+		Nombre:          participant.Nombre,
+		Rol:             models.RolCorredor,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer(),
+			Audience:  jwt.ClaimStrings{ParticipantAudience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(tokenLifetime)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	})
+}
+
+// GenerateUserToken crea el token de una cuenta que inició sesión, con su rol.
+// Las cuentas de corredor reciben un token de participante.
+func GenerateUserToken(user models.User) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		Rol: user.Rol,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user.Username,
+			Issuer:    tokenIssuer(),
+			Audience:  jwt.ClaimStrings{StaffAudience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(staffTokenLifetime)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+	if user.Rol == models.RolCorredor {
+		claims.ParticipantID = user.ParticipantID
+		claims.Audience = jwt.ClaimStrings{ParticipantAudience}
+		claims.ExpiresAt = jwt.NewNumericDate(now.Add(tokenLifetime))
+	}
+	return signToken(claims)
+}
+
+func signToken(claims Claims) (string, error) {
 	// CAMBIO CLAVE: Leemos la variable de entorno DENTRO de la función.
 	var jwtSecretKey = []byte(os.Getenv("JWT_SECRET_KEY"))
 
@@ -19,15 +88,6 @@ func GenerateToken(participant models.Participant) (string, error) {
 		return "", fmt.Errorf("la clave secreta JWT_SECRET_KEY no está configurada o no se pudo leer del .env")
 	}
 
-	// El resto de la función es exactamente igual.
-	claims := jwt.MapClaims{
-		"participant_id": participant.ID,
-		"email":          participant.Email,
-		"nombre":         participant.Nombre,
-		"exp":            time.Now().Add(time.Hour * 72).Unix(),
-		"iat":            time.Now().Unix(),
-	}
-
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	tokenString, err := token.SignedString(jwtSecretKey)
@@ -36,4 +96,66 @@ func GenerateToken(participant models.Participant) (string, error) {
 	}
 
 	return tokenString, nil
-}
\ No newline at end of file
+}
+
+// VerifyToken comprueba que el token lo firmó este servidor con HS256, que
+// está vigente (exp e iat obligatorios, iat no futuro), que su emisor es este
+// servicio y que su audiencia corresponde a su rol. Los tokens sin rol,
+// emitidos antes de que existieran las cuentas, son de corredor. Los que no
+// traen emisor ni audiencia, de antes de esta verificación, se rechazan: el
+// corredor obtiene uno nuevo al registrarse o al iniciar sesión.
+func VerifyToken(tokenString string) (*Claims, error) {
+	var jwtSecretKey = []byte(os.Getenv("JWT_SECRET_KEY"))
+	if len(jwtSecretKey) == 0 {
+		return nil, fmt.Errorf("la clave secreta JWT_SECRET_KEY no está configurada o no se pudo leer del .env")
+	}
+
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return jwtSecretKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return nil, fmt.Errorf("token inválido: %w", err)
+	}
+	if claims.Rol == "" {
+		claims.Rol = models.RolCorredor
+	}
+
+	// ParseWithClaims solo valida exp e iat si vienen en el token
+	switch {
+	case claims.ExpiresAt == nil:
+		return nil, fmt.Errorf("token inválido: no indica su vencimiento (exp)")
+	case claims.IssuedAt == nil:
+		return nil, fmt.Errorf("token inválido: no indica su emisión (iat)")
+	case !claims.VerifyIssuer(tokenIssuer(), true):
+		return nil, fmt.Errorf("token inválido: emisor desconocido")
+	case !models.IsRol(claims.Rol):
+		return nil, fmt.Errorf("token inválido: rol desconocido '%s'", claims.Rol)
+	case claims.Rol == models.RolCorredor && !claims.VerifyAudience(ParticipantAudience, true):
+		return nil, fmt.Errorf("token inválido: no está dirigido a participantes")
+	case claims.Rol == models.RolCorredor && claims.ParticipantID == 0:
+		return nil, fmt.Errorf("token inválido: no identifica a un participante")
+	case claims.Rol != models.RolCorredor && !claims.VerifyAudience(StaffAudience, true):
+		return nil, fmt.Errorf("token inválido: no está dirigido al personal")
+	case claims.Rol != models.RolCorredor && claims.Subject == "":
+		return nil, fmt.Errorf("token inválido: no identifica al usuario")
+	}
+	return claims, nil
+}
+
+// Actor identifica en la auditoría a quien usa el token: el usuario de la
+// cuenta o, en los tokens de registro, el email del participante.
+func (c *Claims) Actor() string {
+	if c.Subject != "" {
+		return c.Subject
+	}
+	return c.Email
+}
+
+// tokenIssuer es el emisor de los tokens (JWT_ISSUER, o el nombre del servicio).
+func tokenIssuer() string {
+	if issuer := os.Getenv("JWT_ISSUER"); issuer != "" {
+		return issuer
+	}
+	return defaultIssuer
+}
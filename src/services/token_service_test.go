@@ -0,0 +1,216 @@
+package services
+
+import (
+	"compilerciclista/src/models"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const testSecret = "secreto-de-prueba"
+
+func TestGeneratedTokenVerifies(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", testSecret)
+
+	token, err := GenerateToken(models.Participant{ID: 7, ParticipantCode: "ELI-001", Email: "ana@unam.mx"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := VerifyToken(token)
+	if err != nil {
+		t.Fatalf("VerifyToken rechazó un token de participante: %v", err)
+	}
+	if claims.ParticipantID != 7 || claims.Rol != models.RolCorredor || claims.Actor() != "ana@unam.mx" {
+		t.Errorf("claims = %+v", claims)
+	}
+}
+
+func TestGeneratedUserTokensVerify(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", testSecret)
+
+	token, err := GenerateUserToken(models.User{Username: "mesa1", Rol: models.RolMesa})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := VerifyToken(token)
+	if err != nil {
+		t.Fatalf("VerifyToken rechazó un token del personal: %v", err)
+	}
+	if claims.Rol != models.RolMesa || claims.Actor() != "mesa1" || !claims.VerifyAudience(StaffAudience, true) {
+		t.Errorf("claims = %+v", claims)
+	}
+
+	token, err = GenerateUserToken(models.User{Username: "ana", Rol: models.RolCorredor, ParticipantID: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err = VerifyToken(token)
+	if err != nil {
+		t.Fatalf("VerifyToken rechazó el token de una cuenta de corredor: %v", err)
+	}
+	if claims.ParticipantID != 7 || !claims.VerifyAudience(ParticipantAudience, true) {
+		t.Errorf("claims = %+v", claims)
+	}
+}
+
+func TestVerifyTokenWithoutRole(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", testSecret)
+	now := time.Now()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+		ParticipantID: 7,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    defaultIssuer,
+			Audience:  jwt.ClaimStrings{ParticipantAudience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := VerifyToken(token)
+	if err != nil {
+		t.Fatalf("VerifyToken rechazó un token sin rol: %v", err)
+	}
+	if claims.Rol != models.RolCorredor {
+		t.Errorf("Rol = %q, se esperaba %q", claims.Rol, models.RolCorredor)
+	}
+}
+
+func TestVerifyTokenRejects(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", testSecret)
+	now := time.Now()
+
+	// valid es un token de participante correcto; cada caso cambia algo.
+	valid := func() Claims {
+		return Claims{
+			ParticipantID: 7,
+			Rol:           models.RolCorredor,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    defaultIssuer,
+				Audience:  jwt.ClaimStrings{ParticipantAudience},
+				ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+				IssuedAt:  jwt.NewNumericDate(now),
+			},
+		}
+	}
+	sign := func(claims Claims, method jwt.SigningMethod, key interface{}) string {
+		token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	tests := []struct {
+		name  string
+		token func() string
+	}{
+		{"vencido", func() string {
+			c := valid()
+			c.ExpiresAt = jwt.NewNumericDate(now.Add(-time.Minute))
+			return sign(c, jwt.SigningMethodHS256, []byte(testSecret))
+		}},
+		{"sin exp", func() string {
+			c := valid()
+			c.ExpiresAt = nil
+			return sign(c, jwt.SigningMethodHS256, []byte(testSecret))
+		}},
+		{"sin iat", func() string {
+			c := valid()
+			c.IssuedAt = nil
+			return sign(c, jwt.SigningMethodHS256, []byte(testSecret))
+		}},
+		{"emitido en el futuro", func() string {
+			c := valid()
+			c.IssuedAt = jwt.NewNumericDate(now.Add(time.Hour))
+			return sign(c, jwt.SigningMethodHS256, []byte(testSecret))
+		}},
+		{"otro emisor", func() string {
+			c := valid()
+			c.Issuer = "otro-servicio"
+			return sign(c, jwt.SigningMethodHS256, []byte(testSecret))
+		}},
+		{"sin emisor", func() string {
+			c := valid()
+			c.Issuer = ""
+			return sign(c, jwt.SigningMethodHS256, []byte(testSecret))
+		}},
+		{"corredor con audiencia del personal", func() string {
+			c := valid()
+			c.Audience = jwt.ClaimStrings{StaffAudience}
+			return sign(c, jwt.SigningMethodHS256, []byte(testSecret))
+		}},
+		{"personal con audiencia de participantes", func() string {
+			c := valid()
+			c.Rol = models.RolAdmin
+			c.Subject = "admin"
+			return sign(c, jwt.SigningMethodHS256, []byte(testSecret))
+		}},
+		{"personal sin usuario", func() string {
+			c := valid()
+			c.Rol = models.RolJuez
+			c.Audience = jwt.ClaimStrings{StaffAudience}
+			return sign(c, jwt.SigningMethodHS256, []byte(testSecret))
+		}},
+		{"rol desconocido", func() string {
+			c := valid()
+			c.Rol = "superusuario"
+			c.Subject = "x"
+			c.Audience = jwt.ClaimStrings{StaffAudience}
+			return sign(c, jwt.SigningMethodHS256, []byte(testSecret))
+		}},
+		{"sin audiencia", func() string {
+			c := valid()
+			c.Audience = nil
+			return sign(c, jwt.SigningMethodHS256, []byte(testSecret))
+		}},
+		{"corredor sin participante", func() string {
+			c := valid()
+			c.ParticipantID = 0
+			return sign(c, jwt.SigningMethodHS256, []byte(testSecret))
+		}},
+		{"anterior a la verificación, sin emisor ni audiencia", func() string {
+			c := valid()
+			c.Rol = ""
+			c.Issuer = ""
+			c.Audience = nil
+			return sign(c, jwt.SigningMethodHS256, []byte(testSecret))
+		}},
+		{"otra clave", func() string {
+			return sign(valid(), jwt.SigningMethodHS256, []byte("otra-clave"))
+		}},
+		{"otro algoritmo", func() string {
+			return sign(valid(), jwt.SigningMethodHS512, []byte(testSecret))
+		}},
+		{"sin firma", func() string {
+			return sign(valid(), jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if claims, err := VerifyToken(tt.token()); err == nil {
+				t.Errorf("VerifyToken aceptó el token: %+v", claims)
+			}
+		})
+	}
+}
+
+func TestVerifyTokenUsesConfiguredIssuer(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", testSecret)
+	t.Setenv("JWT_ISSUER", "carrera-2026")
+	token, err := GenerateToken(models.Participant{ID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyToken(token); err != nil {
+		t.Fatalf("VerifyToken rechazó un token con el emisor configurado: %v", err)
+	}
+
+	t.Setenv("JWT_ISSUER", "")
+	if _, err := VerifyToken(token); err == nil {
+		t.Error("VerifyToken aceptó un token de otro emisor")
+	}
+}
@@ -1,5 +1,7 @@
 package token
 
+import "fmt"
+
 type TokenType string
 
 const (
@@ -9,13 +11,49 @@ const (
 	IDENT  = "IDENT"
 	STRING = "STRING"
 	BOOL   = "BOOL"
+	INT    = "INT"
+	FLOAT  = "FLOAT"
+	DATE   = "DATE" // 2001-05-14
+
+	// COMMENT nunca lo devuelve NextToken; el lexer guarda los comentarios
+	// aparte para herramientas que necesitan conservarlos.
+	COMMENT = "COMMENT"
 
 	COLON     = ":"
 	SEMICOLON = ";"
+	COMMA     = ","
+	LBRACKET  = "["
+	RBRACKET  = "]"
+	LBRACE    = "{"
+	RBRACE    = "}"
 )
 
+// Position indica un punto dentro del documento fuente.
+// Line y Column empiezan en 1; Offset es el desplazamiento en bytes desde el inicio.
+type Position struct {
+	Offset int `json:"offset"`
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// IsValid indica si la posición fue asignada por el lexer.
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// Span es el rango [Start, End) que ocupa un elemento en el documento fuente.
+type Span struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
 // Estructura de un Token
 type Token struct {
 	Type    TokenType
 	Literal string
-}
\ No newline at end of file
+	Span    Span
+}